@@ -15,6 +15,14 @@ const (
 	AirflowReleaseURL = "https://updates.astronomer.io/astronomer-certified"
 )
 
+// httpClient is the client of the version lookups which are not given one, see SetHTTPClient
+var httpClient = httputil.NewHTTPClient()
+
+// SetHTTPClient sets the client used by NewDefaultClient and Request.Do, e.g. to honor the proxy and certificates settings
+func SetHTTPClient(c *httputil.HTTPClient) {
+	httpClient = c
+}
+
 // Client containers the logger and HTTPClient used to communicate with the HoustonAPI
 type Client struct {
 	HTTPClient             *httputil.HTTPClient
@@ -29,6 +37,11 @@ func NewClient(c *httputil.HTTPClient, useAstronomerCertified bool) *Client {
 	}
 }
 
+// NewDefaultClient returns a new Client using the client set with SetHTTPClient
+func NewDefaultClient(useAstronomerCertified bool) *Client {
+	return NewClient(httpClient, useAstronomerCertified)
+}
+
 // Request represents empty request
 type Request struct{}
 
@@ -45,7 +58,7 @@ func (r *Request) DoWithClient(api *Client) (*Response, error) {
 
 // Do executes the given HTTP request and returns the HTTP Response
 func (r *Request) Do() (*Response, error) {
-	return r.DoWithClient(NewClient(httputil.NewHTTPClient(), false))
+	return r.DoWithClient(NewDefaultClient(false))
 }
 
 // Do executes a query against the updates astronomer API, logging out any errors contained in the response object
@@ -32,3 +32,14 @@ func TestClientDo(t *testing.T) {
 		assert.Equal(t, mockResp, *resp)
 	})
 }
+
+func TestNewDefaultClient(t *testing.T) {
+	originalHTTPClient := httpClient
+	defer func() { httpClient = originalHTTPClient }()
+
+	c := httputil.NewHTTPClient()
+	SetHTTPClient(c)
+	client := NewDefaultClient(true)
+	assert.Equal(t, c, client.HTTPClient)
+	assert.True(t, client.useAstronomerCertified)
+}
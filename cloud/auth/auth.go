@@ -58,6 +58,11 @@ var (
 	userEmail       = ""
 )
 
+// SetHTTPClient sets the client used for the authentication calls
+func SetHTTPClient(c *httputil.HTTPClient) {
+	httpClient = c
+}
+
 var authenticator = Authenticator{
 	orgChecker:      orgLookup,
 	tokenRequester:  requestToken,
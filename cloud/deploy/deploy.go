@@ -20,7 +20,6 @@ import (
 	"github.com/astronomer/astro-cli/pkg/ansi"
 	"github.com/astronomer/astro-cli/pkg/azure"
 	"github.com/astronomer/astro-cli/pkg/fileutil"
-	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/pkg/input"
 	"github.com/astronomer/astro-cli/pkg/util"
 	"github.com/docker/docker/api/types/versions"
@@ -695,7 +694,7 @@ func ValidTags(runtimeVersions []string, currentVersion string) []string {
 }
 
 func CheckVersion(version string, out io.Writer) {
-	httpClient := airflowversions.NewClient(httputil.NewHTTPClient(), false)
+	httpClient := airflowversions.NewDefaultClient(false)
 	latestRuntimeVersion, _ := airflowversions.GetDefaultImageTag(httpClient, "")
 	switch {
 	case versions.LessThan(version, latestRuntimeVersion):
@@ -14,7 +14,6 @@ import (
 	"github.com/astronomer/astro-cli/config"
 	"github.com/astronomer/astro-cli/pkg/ansi"
 	"github.com/astronomer/astro-cli/pkg/domainutil"
-	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/pkg/input"
 	"github.com/astronomer/astro-cli/pkg/printutil"
 	"github.com/astronomer/astro-cli/pkg/util"
@@ -681,7 +680,7 @@ func deploymentSelectionProcess(ws string, deployments []astro.Deployment, clien
 	}
 	if currentDeployment.ID == "" {
 		// get latest runtime version
-		airflowVersionClient := airflowversions.NewClient(httputil.NewHTTPClient(), false)
+		airflowVersionClient := airflowversions.NewDefaultClient(false)
 		runtimeVersion, err := airflowversions.GetDefaultImageTag(airflowVersionClient, "")
 		if err != nil {
 			return astro.Deployment{}, err
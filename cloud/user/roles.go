@@ -0,0 +1,149 @@
+package user
+
+import (
+	httpContext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/context"
+	"github.com/astronomer/astro-cli/pkg/httputil"
+)
+
+const (
+	OrganizationRoleScope = "ORGANIZATION"
+	WorkspaceRoleScope    = "WORKSPACE"
+	rolesCacheTTL         = 24 * time.Hour
+	rolesFetchTimeout     = 5 * time.Second
+	rolesCacheDirMode     = 0o755
+	rolesCacheFileMode    = 0o600
+)
+
+var (
+	// DefaultRolesCacheFile is where the roles fetched from the core API are cached between commands
+	DefaultRolesCacheFile = filepath.Join(config.HomeConfigPath, "cache", "roles.json")
+
+	// staticRoles are used when the roles cannot be fetched from the core API nor read from the cache
+	staticRoles = map[string][]string{
+		OrganizationRoleScope: {"ORGANIZATION_MEMBER", "ORGANIZATION_BILLING_ADMIN", "ORGANIZATION_OWNER"},
+		WorkspaceRoleScope:    {"WORKSPACE_VIEWER", "WORKSPACE_EDITOR", "WORKSPACE_ADMIN"},
+	}
+
+	// Roles is the client used to validate and complete roles
+	Roles = NewRolesClient(httputil.NewHTTPClient(), DefaultRolesCacheFile)
+)
+
+type role struct {
+	Name      string `json:"name"`
+	ScopeType string `json:"scopeType"`
+}
+
+type rolesResponse struct {
+	Roles []role `json:"roles"`
+}
+
+type cachedRoles struct {
+	Roles     []string  `json:"roles"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// RolesClient fetches the roles which can be assigned in an organization from the core API.
+// Roles are cached on disk per domain, organization and scope, and fall back to a static list when the API is unreachable.
+type RolesClient struct {
+	httpClient *httputil.HTTPClient
+	cacheFile  string
+}
+
+func NewRolesClient(c *httputil.HTTPClient, cacheFile string) *RolesClient {
+	return &RolesClient{httpClient: c, cacheFile: cacheFile}
+}
+
+// GetRoles returns the roles for the given scope, preferring a fresh cache, then the core API,
+// then a stale cache and finally the static list of roles
+func (r *RolesClient) GetRoles(scope string) []string {
+	ctx, err := context.GetCurrentContext()
+	if err != nil || ctx.OrganizationShortName == "" {
+		return staticRoles[scope]
+	}
+	cacheKey := ctx.Domain + "/" + ctx.OrganizationShortName + "/" + scope
+	cache := r.readCache()
+
+	cached, ok := cache[cacheKey]
+	if ok && time.Since(cached.FetchedAt) < rolesCacheTTL {
+		return cached.Roles
+	}
+
+	roles, err := r.fetchRoles(&ctx, scope)
+	if err != nil || len(roles) == 0 {
+		if ok {
+			return cached.Roles
+		}
+		return staticRoles[scope]
+	}
+
+	cache[cacheKey] = cachedRoles{Roles: roles, FetchedAt: time.Now()}
+	r.writeCache(cache)
+	return roles
+}
+
+// fetchRoles calls the core API roles endpoint, which is not part of the generated core client yet.
+// It is called during shell completion, so it gives up quickly and lets GetRoles fall back.
+func (r *RolesClient) fetchRoles(ctx *config.Context, scope string) ([]string, error) {
+	timeoutCtx, cancel := httpContext.WithTimeout(httpContext.Background(), rolesFetchTimeout)
+	defer cancel()
+	resp, err := r.httpClient.Do(&httputil.DoOptions{
+		Context: timeoutCtx,
+		Method:  http.MethodGet,
+		Path:    fmt.Sprintf("%s/organizations/%s/roles?scopeType=%s", ctx.GetPublicRESTAPIURL(), ctx.OrganizationShortName, scope),
+		Headers: map[string]string{"authorization": ctx.Token},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var decoded rolesResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+	roles := []string{}
+	for _, role := range decoded.Roles {
+		if role.ScopeType == "" || role.ScopeType == scope {
+			roles = append(roles, role.Name)
+		}
+	}
+	return roles, nil
+}
+
+func (r *RolesClient) readCache() map[string]cachedRoles {
+	cache := map[string]cachedRoles{}
+	data, err := os.ReadFile(r.cacheFile)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]cachedRoles{}
+	}
+	return cache
+}
+
+// writeCache is best effort, a failure only means the roles are fetched again next time
+func (r *RolesClient) writeCache(cache map[string]cachedRoles) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cacheFile), rolesCacheDirMode); err != nil {
+		return
+	}
+	_ = os.WriteFile(r.cacheFile, data, rolesCacheFileMode)
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRolesResponse = `{"roles": [
+	{"name": "ORGANIZATION_MEMBER", "scopeType": "ORGANIZATION"},
+	{"name": "ORGANIZATION_AUDITOR", "scopeType": "ORGANIZATION"},
+	{"name": "WORKSPACE_OPERATOR", "scopeType": "WORKSPACE"}
+]}`
+
+// patchRoles replaces Roles with a client answering every call with statusCode and body, and returns the number of calls made
+func patchRoles(t *testing.T, statusCode int, body string) *int {
+	calls := 0
+	client := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	originalRoles := Roles
+	Roles = NewRolesClient(client, filepath.Join(t.TempDir(), "roles.json"))
+	t.Cleanup(func() { Roles = originalRoles })
+	return &calls
+}
+
+func TestGetRoles(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+
+	t.Run("roles are fetched from the API and cached", func(t *testing.T) {
+		calls := patchRoles(t, http.StatusOK, testRolesResponse)
+		assert.Equal(t, []string{"ORGANIZATION_MEMBER", "ORGANIZATION_AUDITOR"}, Roles.GetRoles(OrganizationRoleScope))
+		assert.Equal(t, []string{"ORGANIZATION_MEMBER", "ORGANIZATION_AUDITOR"}, Roles.GetRoles(OrganizationRoleScope))
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, []string{"WORKSPACE_OPERATOR"}, Roles.GetRoles(WorkspaceRoleScope))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("static roles are used when the API fails", func(t *testing.T) {
+		patchRoles(t, http.StatusInternalServerError, `{"message": "internal error"}`)
+		assert.Equal(t, staticRoles[OrganizationRoleScope], Roles.GetRoles(OrganizationRoleScope))
+		assert.Equal(t, staticRoles[WorkspaceRoleScope], Roles.GetRoles(WorkspaceRoleScope))
+	})
+
+	t.Run("stale cache is used when the API fails", func(t *testing.T) {
+		calls := patchRoles(t, http.StatusInternalServerError, `{"message": "internal error"}`)
+		Roles.writeCache(map[string]cachedRoles{
+			"astronomer.io/test-org-short-name/" + OrganizationRoleScope: {Roles: []string{"ORGANIZATION_AUDITOR"}, FetchedAt: time.Now().Add(-2 * rolesCacheTTL)},
+		})
+		assert.Equal(t, []string{"ORGANIZATION_AUDITOR"}, Roles.GetRoles(OrganizationRoleScope))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("cache of another domain is not used", func(t *testing.T) {
+		calls := patchRoles(t, http.StatusOK, testRolesResponse)
+		Roles.writeCache(map[string]cachedRoles{
+			"astronomer-dev.io/test-org-short-name/" + OrganizationRoleScope: {Roles: []string{"ORGANIZATION_AUDITOR"}, FetchedAt: time.Now()},
+		})
+		assert.Equal(t, []string{"ORGANIZATION_MEMBER", "ORGANIZATION_AUDITOR"}, Roles.GetRoles(OrganizationRoleScope))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("corrupted cache is ignored", func(t *testing.T) {
+		patchRoles(t, http.StatusOK, testRolesResponse)
+		assert.NoError(t, os.WriteFile(Roles.cacheFile, []byte("not json"), rolesCacheFileMode))
+		assert.Equal(t, []string{"ORGANIZATION_MEMBER", "ORGANIZATION_AUDITOR"}, Roles.GetRoles(OrganizationRoleScope))
+	})
+
+	t.Run("roles are fetched with a timeout", func(t *testing.T) {
+		patchRoles(t, http.StatusOK, testRolesResponse)
+		hasDeadline := false
+		client := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+			_, hasDeadline = req.Context().Deadline()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(testRolesResponse)),
+				Header:     make(http.Header),
+			}
+		})
+		Roles = NewRolesClient(client, filepath.Join(t.TempDir(), "roles.json"))
+		Roles.GetRoles(OrganizationRoleScope)
+		assert.True(t, hasDeadline)
+	})
+}
@@ -0,0 +1,131 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/httputil"
+)
+
+// teamsPageSize is the number of teams requested per page when listing the teams
+const teamsPageSize = 100
+
+// Teams is the client used to assign invited users to teams
+var Teams = NewTeamsClient(httputil.NewHTTPClient())
+
+// Team is a team of an organization
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type teamsResponse struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	TotalCount int    `json:"totalCount"`
+	Teams      []Team `json:"teams"`
+}
+
+type teamMemberRequest struct {
+	UserID string `json:"userId"`
+}
+
+// TeamsClient manages the teams of an organization through the core API.
+// The teams endpoints are not part of the generated core client yet, so they are called directly.
+type TeamsClient struct {
+	httpClient *httputil.HTTPClient
+}
+
+func NewTeamsClient(c *httputil.HTTPClient) *TeamsClient {
+	return &TeamsClient{httpClient: c}
+}
+
+func (c *TeamsClient) do(ctx *config.Context, method, path string, data []byte) (*http.Response, error) {
+	return c.httpClient.Do(&httputil.DoOptions{
+		Method:  method,
+		Path:    fmt.Sprintf("%s/organizations/%s%s", ctx.GetPublicRESTAPIURL(), ctx.OrganizationShortName, path),
+		Data:    data,
+		Headers: map[string]string{"authorization": ctx.Token},
+	})
+}
+
+// ListTeams returns the teams of the organization of ctx, following the pages of the teams endpoint
+func (c *TeamsClient) ListTeams(ctx *config.Context) ([]Team, error) {
+	teams := []Team{}
+	for {
+		page, err := c.listTeamsPage(ctx, len(teams))
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, page.Teams...)
+		if len(page.Teams) < teamsPageSize || len(teams) >= page.TotalCount {
+			return teams, nil
+		}
+	}
+}
+
+func (c *TeamsClient) listTeamsPage(ctx *config.Context, offset int) (*teamsResponse, error) {
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/teams?offset=%d&limit=%d", offset, teamsPageSize), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var decoded teamsResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// ResolveTeams returns the teams matching names, in the same order.
+// error ErrTeamNotFound is returned if any of the names is not a team of the organization
+func (c *TeamsClient) ResolveTeams(ctx *config.Context, names []string) ([]Team, error) {
+	teams, err := c.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teamsByName := map[string]Team{}
+	for _, team := range teams {
+		teamsByName[team.Name] = team
+	}
+	resolved := make([]Team, 0, len(names))
+	for _, name := range names {
+		team, ok := teamsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTeamNotFound, name)
+		}
+		resolved = append(resolved, team)
+	}
+	return resolved, nil
+}
+
+// AddTeamMember adds the user to the team
+func (c *TeamsClient) AddTeamMember(ctx *config.Context, teamID, userID string) error {
+	data, err := json.Marshal(teamMemberRequest{UserID: userID})
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/teams/"+url.PathEscape(teamID)+"/members", data)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// RemoveTeamMember removes the user from the team
+func (c *TeamsClient) RemoveTeamMember(ctx *config.Context, teamID, userID string) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/teams/"+url.PathEscape(teamID)+"/members/"+url.PathEscape(userID), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	astrocore_mocks "github.com/astronomer/astro-cli/astro-client-core/mocks"
+	"github.com/astronomer/astro-cli/config"
+	testUtil "github.com/astronomer/astro-cli/pkg/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTeamsResponse = `{"teams": [{"id": "team-data-id", "name": "data"}, {"id": "team-ml-id", "name": "ml"}]}`
+
+// patchTeams replaces Teams with a client answering with the status code set for each "METHOD path", 200 by default
+func patchTeams(t *testing.T, statusCodes map[string]int) *[]string {
+	calls := []string{}
+	client := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+		call := req.Method + " " + req.URL.Path
+		calls = append(calls, call)
+		statusCode, ok := statusCodes[call]
+		if !ok {
+			statusCode = http.StatusOK
+		}
+		body := "{}"
+		if req.Method == http.MethodGet {
+			body = testTeamsResponse
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	originalTeams := Teams
+	Teams = NewTeamsClient(client)
+	t.Cleanup(func() { Teams = originalTeams })
+	return &calls
+}
+
+func TestCreateInviteWithTeams(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, http.StatusInternalServerError, "")
+	inviteUserID := "user_cuid"
+	createInviteResponseOK := astrocore.CreateUserInviteResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+		},
+		JSON200: &astrocore.Invite{
+			InviteId: "test-invite-id",
+			UserId:   &inviteUserID,
+		},
+	}
+	deleteInviteResponseOK := astrocore.DeleteUserInviteResponse{
+		HTTPResponse: &http.Response{
+			StatusCode: 204,
+		},
+	}
+
+	t.Run("happy path adds the user to every team", func(t *testing.T) {
+		calls := patchTeams(t, nil)
+		out := new(bytes.Buffer)
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data", "ml"}, out, mockClient)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "added to teams: data, ml\n")
+		assert.Equal(t, []string{
+			"GET /v1alpha1/organizations/test-org-short-name/teams",
+			"POST /v1alpha1/organizations/test-org-short-name/teams/team-data-id/members",
+			"POST /v1alpha1/organizations/test-org-short-name/teams/team-ml-id/members",
+		}, *calls)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("error path when a team does not exist", func(t *testing.T) {
+		patchTeams(t, nil)
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data", "unknown"}, new(bytes.Buffer), mockClient)
+		assert.ErrorIs(t, err, ErrTeamNotFound)
+		mockClient.AssertNotCalled(t, "CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("error path rolls back the memberships and the invite", func(t *testing.T) {
+		calls := patchTeams(t, map[string]int{"POST /v1alpha1/organizations/test-org-short-name/teams/team-ml-id/members": http.StatusInternalServerError})
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
+		mockClient.On("DeleteUserInviteWithResponse", mock.Anything, "test-org-short-name", "test-invite-id").Return(&deleteInviteResponseOK, nil).Once()
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data", "ml"}, new(bytes.Buffer), mockClient)
+		assert.ErrorContains(t, err, "failed to add user to team ml")
+		assert.ErrorContains(t, err, "The invite was rolled back")
+		assert.Contains(t, *calls, "DELETE /v1alpha1/organizations/test-org-short-name/teams/team-data-id/members/user_cuid")
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("error path when rolling back fails", func(t *testing.T) {
+		patchTeams(t, map[string]int{
+			"POST /v1alpha1/organizations/test-org-short-name/teams/team-ml-id/members":               http.StatusInternalServerError,
+			"DELETE /v1alpha1/organizations/test-org-short-name/teams/team-data-id/members/user_cuid": http.StatusInternalServerError,
+		})
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
+		mockClient.On("DeleteUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(nil, errorNetwork).Once()
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data", "ml"}, new(bytes.Buffer), mockClient)
+		assert.ErrorContains(t, err, "rolling back failed")
+		assert.ErrorContains(t, err, "membership of team data")
+		assert.ErrorContains(t, err, "invite test-invite-id (network error)")
+	})
+
+	t.Run("invite without a user is kept and teams are skipped", func(t *testing.T) {
+		calls := patchTeams(t, nil)
+		createInviteResponseNoUser := astrocore.CreateUserInviteResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: 200,
+			},
+			JSON200: &astrocore.Invite{
+				InviteId: "test-invite-id",
+			},
+		}
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseNoUser, nil).Twice()
+		out := new(bytes.Buffer)
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data"}, out, mockClient)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "invite id: test-invite-id\n")
+		assert.Contains(t, out.String(), "WARNING! The invite does not have a user yet, so the user was not added to teams data.")
+		assert.NotContains(t, out.String(), "added to teams: ")
+		assert.Equal(t, []string{"GET /v1alpha1/organizations/test-org-short-name/teams"}, *calls)
+
+		out = new(bytes.Buffer)
+		err = CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", JSONOutputFormat, []string{"data"}, out, mockClient)
+		assert.NoError(t, err)
+		var inviteOutput InviteOutput
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &inviteOutput))
+		assert.Empty(t, inviteOutput.Teams)
+		assert.Len(t, inviteOutput.Warnings, 1)
+		mockClient.AssertNotCalled(t, "DeleteUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything)
+		mockClient.AssertExpectations(t)
+	})
+	t.Run("invite without a response body is kept and teams are skipped", func(t *testing.T) {
+		calls := patchTeams(t, nil)
+		createInviteResponseNoBody := astrocore.CreateUserInviteResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: 200,
+			},
+		}
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseNoBody, nil).Once()
+		out := new(bytes.Buffer)
+		err := CreateInviteWithTeams("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, []string{"data"}, out, mockClient)
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "WARNING! The invite does not have a user yet, so the user was not added to teams data.")
+		assert.Equal(t, []string{"GET /v1alpha1/organizations/test-org-short-name/teams"}, *calls)
+		mockClient.AssertNotCalled(t, "DeleteUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestListTeamsPagination(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	ctx, err := config.GetCurrentContext()
+	assert.NoError(t, err)
+
+	offsets := []string{}
+	client := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+		offset := req.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		assert.Equal(t, strconv.Itoa(teamsPageSize), req.URL.Query().Get("limit"))
+		page := teamsResponse{Limit: teamsPageSize, TotalCount: teamsPageSize + 1}
+		if offset == "0" {
+			for i := 0; i < teamsPageSize; i++ {
+				page.Teams = append(page.Teams, Team{ID: fmt.Sprintf("team-%d", i), Name: fmt.Sprintf("team %d", i)})
+			}
+		} else {
+			page.Offset = teamsPageSize
+			page.Teams = []Team{{ID: "team-last", Name: "last"}}
+		}
+		body, err := json.Marshal(page)
+		assert.NoError(t, err)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	teams, err := NewTeamsClient(client).ListTeams(&ctx)
+	assert.NoError(t, err)
+	assert.Len(t, teams, teamsPageSize+1)
+	assert.Equal(t, "last", teams[teamsPageSize].Name)
+	assert.Equal(t, []string{"0", strconv.Itoa(teamsPageSize)}, offsets)
+}
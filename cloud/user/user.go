@@ -2,8 +2,11 @@ package user
 
 import (
 	httpContext "context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	"github.com/astronomer/astro-cli/config"
@@ -13,13 +16,42 @@ import (
 )
 
 var (
-	ErrNoShortName  = errors.New("cannot retrieve organization short name from context")
-	ErrInvalidRole  = errors.New("requested role is invalid. Possible values are ORGANIZATION_MEMBER, ORGANIZATION_BILLING_ADMIN and ORGANIZATION_OWNER ")
-	ErrInvalidEmail = errors.New("no email provided for the invite. Retry with a valid email address")
+	ErrNoShortName         = errors.New("cannot retrieve organization short name from context")
+	ErrInvalidRole         = errors.New("requested role is invalid")
+	ErrInvalidEmail        = errors.New("no email provided for the invite. Retry with a valid email address")
+	ErrInvalidOutputFormat = errors.New("invalid output format. Possible values are text and json")
+	ErrTeamNotFound        = errors.New("team not found")
+	ErrNoInvitedUserID     = errors.New("the invite does not have a user to add to teams")
 )
 
+const (
+	TextOutputFormat = "text"
+	JSONOutputFormat = "json"
+
+	warningTeamsSkippedMsg = "WARNING! The invite does not have a user yet, so the user was not added to teams %s. Add the user to these teams once the invite is accepted."
+)
+
+// InviteOutput is what CreateInvite prints.
+// The core API does not return a link to accept the invite, so only its ID and expiry are surfaced.
+type InviteOutput struct {
+	Email     string   `json:"email"`
+	Role      string   `json:"role"`
+	InviteID  string   `json:"inviteId,omitempty"`
+	ExpiresAt string   `json:"expiresAt,omitempty"`
+	Teams     []string `json:"teams,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
 // CreateInvite calls the CreateUserInvite mutation to create a user invite
-func CreateInvite(email, role string, out io.Writer, client astrocore.CoreClient) error {
+func CreateInvite(email, role, outputFormat string, out io.Writer, client astrocore.CoreClient) error {
+	return CreateInviteWithTeams(email, role, outputFormat, nil, out, client)
+}
+
+// CreateInviteWithTeams creates a user invite like CreateInvite and adds the invited user to the given teams.
+// Teams are resolved before the invite gets created. If adding the user to a team fails,
+// the memberships already added and the invite are rolled back. If the invite does not have a user yet,
+// the invite is kept and a warning says that the teams were skipped.
+func CreateInviteWithTeams(email, role, outputFormat string, teamNames []string, out io.Writer, client astrocore.CoreClient) error {
 	var (
 		userInviteInput astrocore.CreateUserInviteRequest
 		err             error
@@ -32,6 +64,9 @@ func CreateInvite(email, role string, out io.Writer, client astrocore.CoreClient
 	if err != nil {
 		return err
 	}
+	if outputFormat != TextOutputFormat && outputFormat != JSONOutputFormat {
+		return ErrInvalidOutputFormat
+	}
 	ctx, err = context.GetCurrentContext()
 	if err != nil {
 		return err
@@ -39,6 +74,13 @@ func CreateInvite(email, role string, out io.Writer, client astrocore.CoreClient
 	if ctx.OrganizationShortName == "" {
 		return ErrNoShortName
 	}
+	var teams []Team
+	if len(teamNames) > 0 {
+		teams, err = Teams.ResolveTeams(&ctx, teamNames)
+		if err != nil {
+			return err
+		}
+	}
 	userInviteInput = astrocore.CreateUserInviteRequest{
 		InviteeEmail: email,
 		Role:         role,
@@ -51,19 +93,101 @@ func CreateInvite(email, role string, out io.Writer, client astrocore.CoreClient
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(out, "invite for %s with role %s created\n", email, role)
+
+	inviteOutput := InviteOutput{Email: email, Role: role}
+	if len(teams) > 0 {
+		if resp.JSON200 == nil || resp.JSON200.UserId == nil || *resp.JSON200.UserId == "" {
+			inviteOutput.Warnings = append(inviteOutput.Warnings, fmt.Sprintf(warningTeamsSkippedMsg, strings.Join(teamNames, ", ")))
+		} else {
+			if err := addInvitedUserToTeams(&ctx, resp.JSON200, teams, client); err != nil {
+				return err
+			}
+			inviteOutput.Teams = teamNames
+		}
+	}
+	if resp.JSON200 != nil {
+		inviteOutput.InviteID = resp.JSON200.InviteId
+		inviteOutput.ExpiresAt = resp.JSON200.ExpiresAt
+	}
+	return printInvite(&inviteOutput, outputFormat, out)
+}
+
+// addInvitedUserToTeams adds the invited user to teams, rolling back the memberships and the invite on failure
+func addInvitedUserToTeams(ctx *config.Context, invite *astrocore.Invite, teams []Team, client astrocore.CoreClient) error {
+	if invite == nil || invite.UserId == nil {
+		return ErrNoInvitedUserID
+	}
+	added := []Team{}
+	for _, team := range teams {
+		if err := Teams.AddTeamMember(ctx, team.ID, *invite.UserId); err != nil {
+			if rollbackErr := rollbackInvite(ctx, invite, added, client); rollbackErr != nil {
+				return fmt.Errorf("failed to add user to team %s: %w. %s", team.Name, err, rollbackErr.Error())
+			}
+			return fmt.Errorf("failed to add user to team %s: %w. The invite was rolled back", team.Name, err)
+		}
+		added = append(added, team)
+	}
+	return nil
+}
+
+// rollbackInvite removes the invited user from the teams and deletes the invite.
+// The returned error lists what could not be rolled back.
+func rollbackInvite(ctx *config.Context, invite *astrocore.Invite, teams []Team, client astrocore.CoreClient) error {
+	kept := []string{}
+	for _, team := range teams {
+		if err := Teams.RemoveTeamMember(ctx, team.ID, *invite.UserId); err != nil {
+			kept = append(kept, fmt.Sprintf("membership of team %s (%s)", team.Name, err.Error()))
+		}
+	}
+	resp, err := client.DeleteUserInviteWithResponse(httpContext.Background(), ctx.OrganizationShortName, invite.InviteId)
+	if err == nil && resp.HTTPResponse.StatusCode != http.StatusNoContent {
+		err = astrocore.NormalizeAPIError(resp.HTTPResponse, resp.Body)
+	}
+	if err != nil {
+		kept = append(kept, fmt.Sprintf("invite %s (%s)", invite.InviteId, err.Error()))
+	}
+	if len(kept) > 0 {
+		return fmt.Errorf("rolling back failed, these were kept and must be removed manually: %s", strings.Join(kept, ", ")) //nolint:goerr113
+	}
+	return nil
+}
+
+func printInvite(inviteOutput *InviteOutput, outputFormat string, out io.Writer) error {
+	if outputFormat == JSONOutputFormat {
+		data, err := json.MarshalIndent(inviteOutput, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	fmt.Fprintf(out, "invite for %s with role %s created\n", inviteOutput.Email, inviteOutput.Role)
+	if inviteOutput.InviteID != "" {
+		fmt.Fprintf(out, "invite id: %s\n", inviteOutput.InviteID)
+	}
+	if inviteOutput.ExpiresAt != "" {
+		fmt.Fprintf(out, "invite expires at: %s\n", inviteOutput.ExpiresAt)
+	}
+	if len(inviteOutput.Teams) > 0 {
+		fmt.Fprintf(out, "added to teams: %s\n", strings.Join(inviteOutput.Teams, ", "))
+	}
+	for _, warning := range inviteOutput.Warnings {
+		fmt.Fprintln(out, warning)
+	}
 	return nil
 }
 
 // IsRoleValid checks if the requested role is valid
 // If the role is valid, it returns nil
 // error errInvalidRole is returned if the role is not valid
+// The valid roles are the organization roles returned by the core API, see RolesClient.
+// Unless they are cached, fetching them is a network call which may take up to 5 seconds before falling back.
 func IsRoleValid(role string) error {
-	validRoles := []string{"ORGANIZATION_MEMBER", "ORGANIZATION_BILLING_ADMIN", "ORGANIZATION_OWNER"}
+	validRoles := Roles.GetRoles(OrganizationRoleScope)
 	for _, validRole := range validRoles {
 		if role == validRole {
 			return nil
 		}
 	}
-	return ErrInvalidRole
+	return fmt.Errorf("%w. Possible values are %s", ErrInvalidRole, strings.Join(validRoles, ", "))
 }
@@ -31,6 +31,7 @@ func (t testWriter) Write(p []byte) (n int, err error) {
 
 func TestCreateInvite(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, http.StatusInternalServerError, "")
 	inviteUserID := "user_cuid"
 	createInviteResponseOK := astrocore.CreateUserInviteResponse{
 		HTTPResponse: &http.Response{
@@ -60,11 +61,66 @@ func TestCreateInvite(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, createInviteRequest).Return(&createInviteResponseOK, nil).Once()
-		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", out, mockClient)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
 		assert.NoError(t, err)
 		assert.Equal(t, expectedOutMessage, out.String())
 	})
 
+	t.Run("happy path prints the invite id and expiry", func(t *testing.T) {
+		expectedOutMessage := "invite for test-email@test.com with role ORGANIZATION_MEMBER created\n" +
+			"invite id: test-invite-id\n" +
+			"invite expires at: 2023-01-08T00:00:00Z\n"
+		createInviteResponseWithLink := astrocore.CreateUserInviteResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: 200,
+			},
+			JSON200: &astrocore.Invite{
+				InviteId:  "test-invite-id",
+				ExpiresAt: "2023-01-08T00:00:00Z",
+			},
+		}
+		out := new(bytes.Buffer)
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseWithLink, nil).Once()
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOutMessage, out.String())
+	})
+
+	t.Run("happy path with json output", func(t *testing.T) {
+		createInviteResponseWithLink := astrocore.CreateUserInviteResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: 200,
+			},
+			JSON200: &astrocore.Invite{
+				InviteId:  "test-invite-id",
+				ExpiresAt: "2023-01-08T00:00:00Z",
+			},
+		}
+		out := new(bytes.Buffer)
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseWithLink, nil).Once()
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", JSONOutputFormat, out, mockClient)
+		assert.NoError(t, err)
+		var inviteOutput InviteOutput
+		err = json.Unmarshal(out.Bytes(), &inviteOutput)
+		assert.NoError(t, err)
+		assert.Equal(t, InviteOutput{
+			Email:     "test-email@test.com",
+			Role:      "ORGANIZATION_MEMBER",
+			InviteID:  "test-invite-id",
+			ExpiresAt: "2023-01-08T00:00:00Z",
+		}, inviteOutput)
+	})
+
+	t.Run("error path when output format is invalid", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", "yaml", out, mockClient)
+		assert.ErrorIs(t, err, ErrInvalidOutputFormat)
+		mockClient.AssertNotCalled(t, "CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("error path when CreateUserInviteWithResponse return network error", func(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
@@ -73,7 +129,7 @@ func TestCreateInvite(t *testing.T) {
 			Role:         "ORGANIZATION_MEMBER",
 		}
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, createInviteRequest).Return(nil, errorNetwork).Once()
-		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", out, mockClient)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
 		assert.EqualError(t, err, "network error")
 	})
 
@@ -86,7 +142,7 @@ func TestCreateInvite(t *testing.T) {
 			Role:         "ORGANIZATION_MEMBER",
 		}
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, createInviteRequest).Return(&createInviteResponseError, nil).Once()
-		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", out, mockClient)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
 		assert.EqualError(t, err, expectedOutMessage)
 	})
 	t.Run("error path when isValidRole returns an error", func(t *testing.T) {
@@ -94,7 +150,7 @@ func TestCreateInvite(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
-		err := CreateInvite("test-email@test.com", "test-role", out, mockClient)
+		err := CreateInvite("test-email@test.com", "test-role", TextOutputFormat, out, mockClient)
 		assert.ErrorIs(t, err, ErrInvalidRole)
 		assert.Equal(t, expectedOutMessage, out.String())
 	})
@@ -108,7 +164,7 @@ func TestCreateInvite(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
-		err = CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", out, mockClient)
+		err = CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
 		assert.ErrorIs(t, err, ErrNoShortName)
 	})
 
@@ -118,7 +174,7 @@ func TestCreateInvite(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
-		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", out, mockClient)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, out, mockClient)
 		assert.Error(t, err)
 		assert.Equal(t, expectedOutMessage, out.String())
 	})
@@ -127,7 +183,7 @@ func TestCreateInvite(t *testing.T) {
 		out := new(bytes.Buffer)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
-		err := CreateInvite("", "test-role", out, mockClient)
+		err := CreateInvite("", "test-role", TextOutputFormat, out, mockClient)
 		assert.ErrorIs(t, err, ErrInvalidEmail)
 		assert.Equal(t, expectedOutMessage, out.String())
 	})
@@ -135,12 +191,14 @@ func TestCreateInvite(t *testing.T) {
 		testUtil.InitTestConfig(testUtil.CloudPlatform)
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseError, nil).Once()
-		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", testWriter{Error: errorInvite}, mockClient)
+		err := CreateInvite("test-email@test.com", "ORGANIZATION_MEMBER", TextOutputFormat, testWriter{Error: errorInvite}, mockClient)
 		assert.EqualError(t, err, "failed to create invite: test-inv-error")
 	})
 }
 
 func TestIsRoleValid(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, http.StatusInternalServerError, "")
 	var err error
 	t.Run("happy path when role is ORGANIZATION_MEMBER", func(t *testing.T) {
 		err = IsRoleValid("ORGANIZATION_MEMBER")
@@ -157,5 +215,13 @@ func TestIsRoleValid(t *testing.T) {
 	t.Run("error path", func(t *testing.T) {
 		err = IsRoleValid("test")
 		assert.ErrorIs(t, err, ErrInvalidRole)
+		assert.EqualError(t, err, "requested role is invalid. Possible values are ORGANIZATION_MEMBER, ORGANIZATION_BILLING_ADMIN, ORGANIZATION_OWNER")
+	})
+	t.Run("roles fetched from the API are valid", func(t *testing.T) {
+		patchRoles(t, http.StatusOK, testRolesResponse)
+		err = IsRoleValid("ORGANIZATION_AUDITOR")
+		assert.NoError(t, err)
+		err = IsRoleValid("WORKSPACE_OPERATOR")
+		assert.ErrorIs(t, err, ErrInvalidRole)
 	})
 }
@@ -15,7 +15,6 @@ import (
 	"github.com/astronomer/astro-cli/houston"
 	"github.com/astronomer/astro-cli/pkg/ansi"
 	"github.com/astronomer/astro-cli/pkg/fileutil"
-	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/pkg/input"
 	"github.com/astronomer/astro-cli/pkg/util"
 	"github.com/iancoleman/strcase"
@@ -440,7 +439,7 @@ func airflowInit(cmd *cobra.Command, args []string) error {
 	var err error
 	defaultImageTag := runtimeVersion
 	if defaultImageTag == "" {
-		httpClient := airflowversions.NewClient(httputil.NewHTTPClient(), useAstronomerCertified)
+		httpClient := airflowversions.NewDefaultClient(useAstronomerCertified)
 		defaultImageTag = prepareDefaultAirflowImageTag(airflowVersion, httpClient)
 	}
 
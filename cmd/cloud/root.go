@@ -5,6 +5,9 @@ import (
 
 	astro "github.com/astronomer/astro-cli/astro-client"
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
+	"github.com/astronomer/astro-cli/cloud/auth"
+	"github.com/astronomer/astro-cli/cloud/user"
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/spf13/cobra"
 )
 
@@ -25,3 +28,13 @@ func AddCmds(client astro.Client, coreClient astrocore.CoreClient, out io.Writer
 		newUserCmd(out),
 	}
 }
+
+// SetHTTPClient sets the client of the calls which do not go through the astro or core clients,
+// so that they honor the same proxy and certificates settings
+func SetHTTPClient(c *httputil.HTTPClient) {
+	client = c
+	httpClient = c
+	auth.SetHTTPClient(c)
+	user.Roles = user.NewRolesClient(c, user.DefaultRolesCacheFile)
+	user.Teams = user.NewTeamsClient(c)
+}
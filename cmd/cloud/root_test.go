@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	astro_mocks "github.com/astronomer/astro-cli/astro-client/mocks"
+	"github.com/astronomer/astro-cli/cloud/user"
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,3 +19,17 @@ func TestAddCmds(t *testing.T) {
 	}
 	astroMock.AssertExpectations(t)
 }
+
+func TestSetHTTPClient(t *testing.T) {
+	originalClient, originalRoles, originalTeams := client, user.Roles, user.Teams
+	t.Cleanup(func() {
+		SetHTTPClient(originalClient)
+		user.Roles, user.Teams = originalRoles, originalTeams
+	})
+
+	httpClient := httputil.NewHTTPClient()
+	SetHTTPClient(httpClient)
+	assert.Same(t, httpClient, client)
+	assert.NotSame(t, originalRoles, user.Roles)
+	assert.NotSame(t, originalTeams, user.Teams)
+}
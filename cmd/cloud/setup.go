@@ -163,8 +163,6 @@ func refresh(refreshToken string, authConfig astro.AuthConfig) (TokenResponse, e
 		"refresh_token": {refreshToken},
 	}
 
-	client := &http.Client{}
-
 	r, err := http.NewRequestWithContext(http_context.Background(), http.MethodPost, addr, strings.NewReader(data.Encode())) // URL-encoded payload
 	if err != nil {
 		log.Fatal(err)
@@ -172,7 +170,7 @@ func refresh(refreshToken string, authConfig astro.AuthConfig) (TokenResponse, e
 	}
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	res, err := client.Do(r)
+	res, err := client.HTTPClient.Do(r)
 	if err != nil {
 		log.Fatal(err)
 		return TokenResponse{}, fmt.Errorf("cannot get a new access token from the refresh token: %w", err)
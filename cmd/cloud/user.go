@@ -9,7 +9,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var role string
+var (
+	role         string
+	inviteOutput string
+	inviteTeams  []string
+)
 
 func newUserCmd(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -30,17 +34,27 @@ func newUserInviteCmd(out io.Writer) *cobra.Command {
 		Use:     "invite [email]",
 		Aliases: []string{"inv"},
 		Short:   "Invite a user to your Astro Organization",
-		Long: "Invite a user to your Astro Organization\n$astro user invite [email] --role [ORGANIZATION_MEMBER, " +
-			"ORGANIZATION_BILLING_ADMIN, ORGANIZATION_OWNER].",
+		Long: "Invite a user to your Astro Organization\n$astro user invite [email] --role [role].\n" +
+			"The roles available in your Organization are listed by the shell completion of --role.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return userInvite(cmd, args, out)
 		},
 	}
 	cmd.Flags().StringVarP(&role, "role", "r", "ORGANIZATION_MEMBER", "The role for the "+
-		"user. Possible values are ORGANIZATION_MEMBER, ORGANIZATION_BILLING_ADMIN and ORGANIZATION_OWNER ")
+		"user. Use shell completion to list the roles available in your Organization. The role is validated "+
+		"against the roles of your Organization, which may take a few seconds when they are not cached")
+	_ = cmd.RegisterFlagCompletionFunc("role", completeOrganizationRoles)
+	cmd.Flags().StringVarP(&inviteOutput, "output", "o", user.TextOutputFormat, "Output format can be one of: text or json")
+	cmd.Flags().StringSliceVarP(&inviteTeams, "team", "t", []string{}, "The name of a team to add the user to. "+
+		"Can be repeated, the invite is rolled back if the user cannot be added to every team")
 	return cmd
 }
 
+// completeOrganizationRoles completes --role with the organization roles of the current organization
+func completeOrganizationRoles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return user.Roles.GetRoles(user.OrganizationRoleScope), cobra.ShellCompDirectiveNoFileComp
+}
+
 func userInvite(cmd *cobra.Command, args []string, out io.Writer) error {
 	var email string
 
@@ -53,5 +67,5 @@ func userInvite(cmd *cobra.Command, args []string, out io.Writer) error {
 	}
 
 	cmd.SilenceUsage = true
-	return user.CreateInvite(email, role, out, astroCoreClient)
+	return user.CreateInviteWithTeams(email, role, inviteOutput, inviteTeams, out, astroCoreClient)
 }
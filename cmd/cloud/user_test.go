@@ -3,8 +3,10 @@ package cloud
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/astronomer/astro-cli/cloud/user"
@@ -59,9 +61,24 @@ var (
 	}
 )
 
+// patchRoles replaces user.Roles with a client returning body for every roles call
+func patchRoles(t *testing.T, body string) {
+	client := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	originalRoles := user.Roles
+	user.Roles = user.NewRolesClient(client, filepath.Join(t.TempDir(), "roles.json"))
+	t.Cleanup(func() { user.Roles = originalRoles })
+}
+
 func TestUserInvite(t *testing.T) {
-	expectedHelp := "astro user invite [email] --role [ORGANIZATION_MEMBER, ORGANIZATION_BILLING_ADMIN, ORGANIZATION_OWNER]"
+	expectedHelp := "astro user invite [email] --role [role]"
 	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, `{"roles": [{"name": "ORGANIZATION_MEMBER", "scopeType": "ORGANIZATION"}, {"name": "ORGANIZATION_OWNER", "scopeType": "ORGANIZATION"}]}`)
 
 	t.Run("-h prints invite help", func(t *testing.T) {
 		cmdArgs := []string{"invite", "-h"}
@@ -98,6 +115,17 @@ func TestUserInvite(t *testing.T) {
 		_, err := execUserCmd(cmdArgs...)
 		assert.ErrorIs(t, err, user.ErrInvalidRole)
 	})
+	t.Run("valid email with json output prints the invite id", func(t *testing.T) {
+		expectedOut := `"inviteId": "astro_invite_id"`
+		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
+		astroCoreClient = mockClient
+		cmdArgs := []string{"invite", "some@email.com", "--output", "json"}
+		resp, err := execUserCmd(cmdArgs...)
+		assert.NoError(t, err)
+		assert.Contains(t, resp, expectedOut)
+		mockClient.AssertExpectations(t)
+	})
 	t.Run("any errors from api are returned and no invite gets created", func(t *testing.T) {
 		mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
 		mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseError, nil).Once()
@@ -160,3 +188,34 @@ func TestUserInvite(t *testing.T) {
 		assert.ErrorIs(t, err, user.ErrInvalidEmail)
 	})
 }
+
+func TestUserInviteRoleCompletion(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, `{"roles": [{"name": "ORGANIZATION_MEMBER", "scopeType": "ORGANIZATION"}, {"name": "ORGANIZATION_AUDITOR", "scopeType": "ORGANIZATION"}]}`)
+	resp, err := execUserCmd("__complete", "invite", "--role", "")
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "ORGANIZATION_MEMBER\nORGANIZATION_AUDITOR\n")
+}
+
+func TestUserInviteWithTeams(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.CloudPlatform)
+	patchRoles(t, `{"roles": [{"name": "ORGANIZATION_MEMBER", "scopeType": "ORGANIZATION"}]}`)
+	teamsClient := testUtil.NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"teams": [{"id": "team-data-id", "name": "data"}, {"id": "team-ml-id", "name": "ml"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+	originalTeams := user.Teams
+	user.Teams = user.NewTeamsClient(teamsClient)
+	defer func() { user.Teams = originalTeams }()
+
+	mockClient := new(astrocore_mocks.ClientWithResponsesInterface)
+	mockClient.On("CreateUserInviteWithResponse", mock.Anything, mock.Anything, mock.Anything).Return(&createInviteResponseOK, nil).Once()
+	astroCoreClient = mockClient
+	resp, err := execUserCmd("invite", "some@email.com", "--team", "data", "-t", "ml")
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "added to teams: data, ml")
+	mockClient.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	airflowversions "github.com/astronomer/astro-cli/airflow_versions"
 	astro "github.com/astronomer/astro-cli/astro-client"
 	astrocore "github.com/astronomer/astro-cli/astro-client-core"
 	cloudCmd "github.com/astronomer/astro-cli/cmd/cloud"
@@ -33,15 +34,20 @@ const (
 // NewRootCmd adds all of the primary commands for the cli
 func NewRootCmd() *cobra.Command {
 	var err error
-	httpClient := houston.NewHTTPClient()
+	transportConfig := transportConfigFromCFG()
+	httpClient := houston.NewHTTPClient(transportConfig)
+	houston.SetHTTPClient(httpClient)
 	houstonClient = houston.NewClient(httpClient)
 	houstonVersion, err = houstonClient.GetPlatformVersion(nil)
 	if err != nil {
 		softwareCmd.InitDebugLogs = append(softwareCmd.InitDebugLogs, fmt.Sprintf("Unable to get Houston version: %s", err.Error()))
 	}
 
-	astroClient := astro.NewAstroClient(httputil.NewHTTPClient())
-	astroCoreClient := astrocore.NewCoreClient(httputil.NewHTTPClient())
+	astroHTTPClient := newHTTPClient(transportConfig)
+	astroClient := astro.NewAstroClient(astroHTTPClient)
+	astroCoreClient := astrocore.NewCoreClient(astroHTTPClient)
+	cloudCmd.SetHTTPClient(astroHTTPClient)
+	airflowversions.SetHTTPClient(astroHTTPClient)
 
 	ctx := cloudPlatform
 	isCloudCtx := context.IsCloudContext()
@@ -89,7 +95,7 @@ Welcome to the Astro CLI, the modern command line interface for data orchestrati
 
 	if config.CFG.SQLCLI.GetBool() {
 		rootCmd.AddCommand(
-			sql.NewFlowCommand(),
+			sql.NewFlowCommand(transportConfig),
 		)
 	}
 
@@ -110,6 +116,27 @@ Welcome to the Astro CLI, the modern command line interface for data orchestrati
 	return rootCmd
 }
 
+// transportConfigFromCFG returns the proxy and certificates settings of the astro config
+func transportConfigFromCFG() *httputil.TransportConfig {
+	return &httputil.TransportConfig{
+		HTTPProxy:  config.CFG.ProxyHTTP.GetString(),
+		HTTPSProxy: config.CFG.ProxyHTTPS.GetString(),
+		NoProxy:    config.CFG.ProxyNoProxy.GetString(),
+		CABundle:   config.CFG.CertificatesCABundle.GetString(),
+	}
+}
+
+// newHTTPClient returns an HTTP client honoring the proxy and certificates settings,
+// falling back to the default client so a broken CA bundle does not prevent the CLI from starting
+func newHTTPClient(transportConfig *httputil.TransportConfig) *httputil.HTTPClient {
+	httpClient, err := httputil.NewHTTPClientWithTransportConfig(transportConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ignoring proxy and certificates settings: %s\n", err.Error())
+		return httputil.NewHTTPClient()
+	}
+	return httpClient
+}
+
 func getResourcesHelpTemplate(version, ctx string) string {
 	return fmt.Sprintf(`{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
 
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	testUtil "github.com/astronomer/astro-cli/pkg/testing"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -76,3 +78,17 @@ func TestRootCommandWithFlow(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, output, "Run flow commands")
 }
+
+func TestTransportConfigFromCFG(t *testing.T) {
+	testUtil.InitTestConfig(testUtil.LocalPlatform)
+	assert.NoError(t, config.CFG.ProxyHTTPS.SetHomeString("http://proxy.example.com:3128"))
+	assert.NoError(t, config.CFG.ProxyNoProxy.SetHomeString("localhost"))
+	assert.NoError(t, config.CFG.CertificatesCABundle.SetHomeString("/etc/ssl/ca.pem"))
+
+	transportConfig := transportConfigFromCFG()
+	assert.Equal(t, &httputil.TransportConfig{
+		HTTPSProxy: "http://proxy.example.com:3128",
+		NoProxy:    "localhost",
+		CABundle:   "/etc/ssl/ca.pem",
+	}, transportConfig)
+}
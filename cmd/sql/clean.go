@@ -0,0 +1,206 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/astronomer/astro-cli/pkg/input"
+	"github.com/astronomer/astro-cli/sql"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+const dataDirConfigKey = "data_dir"
+
+var (
+	cleanDags   bool
+	cleanData   bool
+	cleanImages bool
+	cleanAll    bool
+	cleanForce  bool
+
+	// imageExists is patched in tests, which do not build the flow image
+	imageExists = sql.ImageExists
+
+	errCleanTargetNotSet = errors.New("nothing to clean, select at least one of --dags, --data, --images or --all")
+	errCleanProjectDir   = errors.New("refusing to remove the project directory or one of its parents")
+)
+
+func getConfigKeyDir(configKey, projectDir string) (string, error) {
+	mountDirs, err := getBaseMountDirs(projectDir)
+	if err != nil {
+		return "", err
+	}
+	dirs, err := appendConfigKeyMountDir(configKey, map[string]string{"project-dir": projectDir}, mountDirs)
+	if err != nil {
+		return "", err
+	}
+	return dirs[len(dirs)-1], nil
+}
+
+// getGeneratedDags returns the DAG files generated for the workflows of the project.
+// Other files of the dags folder may be owned by the user, so they are never selected.
+func getGeneratedDags(projectDir string) ([]string, error) {
+	workflows, err := os.ReadDir(filepath.Join(projectDir, "workflows"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading workflows of %s: %w", projectDir, err)
+	}
+	dagsFolder, err := getConfigKeyDir(dagsFolderConfigKey, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dags := []string{}
+	for _, workflow := range workflows {
+		if !workflow.IsDir() {
+			continue
+		}
+		dag := filepath.Join(dagsFolder, workflow.Name()+".py")
+		if _, err := os.Stat(dag); err == nil {
+			dags = append(dags, dag)
+		}
+	}
+	return dags, nil
+}
+
+// confirmClean lists the paths that are about to be removed and asks for confirmation, unless force is set.
+// Removing generated DAGs within the project is not confirmed, the data directory or anything outside of the project is.
+// A path which is the project directory or one of its parents is never removed.
+func confirmClean(out io.Writer, paths []string, projectDir string, removeData, force bool) (bool, error) {
+	confirm := removeData
+	for _, path := range paths {
+		if sql.IsWithinDir(projectDir, path) {
+			return false, fmt.Errorf("%w: %s", errCleanProjectDir, path)
+		}
+		if !sql.IsWithinDir(path, projectDir) {
+			confirm = true
+		}
+	}
+	if !confirm || force {
+		return true, nil
+	}
+
+	fmt.Fprintln(out, "The following paths will be removed:")
+	for _, path := range paths {
+		if sql.IsWithinDir(path, projectDir) {
+			fmt.Fprintf(out, "  %s\n", path)
+		} else {
+			fmt.Fprintf(out, "  %s (outside of the project directory %s)\n", path, projectDir)
+		}
+	}
+	return input.Confirm("Are you sure you want to delete them?")
+}
+
+func executeClean(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		projectDir = args[0]
+	}
+	if cleanAll {
+		cleanDags, cleanData, cleanImages = true, true, true
+	}
+	if !cleanDags && !cleanData && !cleanImages {
+		return errCleanTargetNotSet
+	}
+
+	out := cmd.OutOrStdout()
+	projectDirAbsolute, err := getAbsolutePath(projectDir)
+	if err != nil {
+		return err
+	}
+
+	// the DAGs and data directories are resolved by the flow image, which is not built only to locate them
+	if cleanDags || cleanData {
+		exists, err := imageExists()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			fmt.Fprintf(out, "Skipping the generated DAGs and data, locating them requires the %s image which is not built\n", sql.SQLCliDockerImageName)
+			cleanDags, cleanData = false, false
+		}
+	}
+
+	var paths []string
+	if cleanDags {
+		dags, err := getGeneratedDags(projectDirAbsolute)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, dags...)
+	}
+	if cleanData {
+		dataDirectory, err := getConfigKeyDir(dataDirConfigKey, projectDirAbsolute)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(dataDirectory); err == nil {
+			paths = append(paths, dataDirectory)
+		}
+	}
+
+	var reclaimed int64
+	if len(paths) > 0 {
+		ok, err := confirmClean(out, paths, projectDirAbsolute, cleanData, cleanForce)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(out, "Skipping the removal of the generated DAGs and data")
+			paths = nil
+		}
+	}
+	for _, path := range paths {
+		size, err := sql.RemovePath(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Removed %s\n", path)
+		reclaimed += size
+	}
+
+	if cleanImages {
+		size, err := sql.RemoveImage()
+		if err != nil {
+			return err
+		}
+		if size > 0 {
+			fmt.Fprintf(out, "Removed image %s\n", sql.SQLCliDockerImageName)
+		}
+		reclaimed += size
+
+		removed, size, err := sql.RemoveDanglingImages()
+		if err != nil {
+			return err
+		}
+		if removed > 0 {
+			fmt.Fprintf(out, "Removed %d dangling images of previous builds\n", removed)
+		}
+		reclaimed += size
+	}
+
+	fmt.Fprintf(out, "Total reclaimed space: %s\n", units.HumanSize(float64(reclaimed)))
+	return nil
+}
+
+func cleanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "clean",
+		Short:        "Remove generated DAGs, data and images of a flow project",
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         executeClean,
+		SilenceUsage: true,
+	}
+	cmd.SetHelpFunc(executeLocalHelp)
+	cmd.Flags().StringVar(&projectDir, "project-dir", ".", "Path of the flow project")
+	cmd.Flags().BoolVar(&cleanDags, "dags", false, "Remove the DAGs generated for the project workflows")
+	cmd.Flags().BoolVar(&cleanData, "data", false, "Remove the project data directory")
+	cmd.Flags().BoolVar(&cleanImages, "images", false, "Remove the flow docker image and the dangling images of its previous builds")
+	cmd.Flags().BoolVar(&cleanAll, "all", false, "Remove generated DAGs, data and images")
+	cmd.Flags().BoolVarP(&cleanForce, "force", "f", false, "Don't prompt a user before removing the data directory or paths outside of the project")
+	return cmd
+}
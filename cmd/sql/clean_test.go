@@ -0,0 +1,198 @@
+package sql
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astronomer/astro-cli/pkg/httputil"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchImageExists makes the clean command see the flow image as built or not
+func patchImageExists(t *testing.T, exists bool) {
+	originalImageExists := imageExists
+	imageExists = func() (bool, error) { return exists, nil }
+	t.Cleanup(func() { imageExists = originalImageExists })
+}
+
+// patchConfigKeyDirs makes the flow config command return the given directories
+func patchConfigKeyDirs(t *testing.T, dagsFolder, dataDirectory string) {
+	patchImageExists(t, true)
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		if configKey == dagsFolderConfigKey {
+			return append(mountDirs, dagsFolder), nil
+		}
+		return append(mountDirs, dataDirectory), nil
+	}
+	t.Cleanup(func() { appendConfigKeyMountDir = originalAppendConfigKeyMountDir })
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// mockStdin feeds input to the next confirmation prompt
+func mockStdin(t *testing.T, input string) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(input))
+	assert.NoError(t, err)
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = stdin })
+}
+
+func TestFlowCleanCmdNoTarget(t *testing.T) {
+	err := execFlowCmd("clean", "--project-dir", t.TempDir())
+	assert.ErrorIs(t, err, errCleanTargetNotSet)
+}
+
+func TestFlowCleanCmdDagsAndData(t *testing.T) {
+	projectDir := t.TempDir()
+	dagsFolder := filepath.Join(projectDir, ".airflow", "dags")
+	dataDirectory := filepath.Join(projectDir, ".airflow", "data")
+	patchConfigKeyDirs(t, dagsFolder, dataDirectory)
+
+	writeTestFile(t, filepath.Join(projectDir, "workflows", "example", "example.sql"), "SELECT 1;")
+	writeTestFile(t, filepath.Join(dagsFolder, "example.py"), "dag")
+	writeTestFile(t, filepath.Join(dagsFolder, "handwritten.py"), "dag")
+	writeTestFile(t, filepath.Join(dataDirectory, "example.db"), "data")
+	mockStdin(t, "y\n")
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--dags", "--data")
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dagsFolder, "example.py"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dagsFolder, "handwritten.py"))
+	assert.NoError(t, err)
+	_, err = os.Stat(dataDirectory)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(projectDir, "workflows", "example", "example.sql"))
+	assert.NoError(t, err)
+}
+
+func TestFlowCleanCmdOutsideProjectDirNotConfirmed(t *testing.T) {
+	projectDir := t.TempDir()
+	dataDirectory := t.TempDir()
+	patchConfigKeyDirs(t, t.TempDir(), dataDirectory)
+	writeTestFile(t, filepath.Join(dataDirectory, "example.db"), "data")
+	mockStdin(t, "n\n")
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--data")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDirectory, "example.db"))
+	assert.NoError(t, err)
+}
+
+func TestFlowCleanCmdOutsideProjectDirConfirmed(t *testing.T) {
+	projectDir := t.TempDir()
+	dataDirectory := t.TempDir()
+	patchConfigKeyDirs(t, t.TempDir(), dataDirectory)
+	writeTestFile(t, filepath.Join(dataDirectory, "example.db"), "data")
+	mockStdin(t, "y\n")
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--data")
+	assert.NoError(t, err)
+	_, err = os.Stat(dataDirectory)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFlowCleanCmdMountsProjectDir(t *testing.T) {
+	projectDir := t.TempDir()
+	dataDirectory := filepath.Join(projectDir, ".airflow", "data")
+	patchImageExists(t, true)
+	var configMountDirs []string
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		configMountDirs = mountDirs
+		return append(mountDirs, dataDirectory), nil
+	}
+	t.Cleanup(func() { appendConfigKeyMountDir = originalAppendConfigKeyMountDir })
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--data")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{projectDir}, configMountDirs)
+}
+
+func TestFlowCleanCmdDataNotConfirmed(t *testing.T) {
+	projectDir := t.TempDir()
+	dataDirectory := filepath.Join(projectDir, ".airflow", "data")
+	patchConfigKeyDirs(t, filepath.Join(projectDir, ".airflow", "dags"), dataDirectory)
+	writeTestFile(t, filepath.Join(dataDirectory, "example.db"), "data")
+	mockStdin(t, "n\n")
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--dags", "--data")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDirectory, "example.db"))
+	assert.NoError(t, err)
+}
+
+func TestFlowCleanCmdDagsWithoutConfirmation(t *testing.T) {
+	projectDir := t.TempDir()
+	dagsFolder := filepath.Join(projectDir, ".airflow", "dags")
+	patchConfigKeyDirs(t, dagsFolder, filepath.Join(projectDir, ".airflow", "data"))
+	writeTestFile(t, filepath.Join(projectDir, "workflows", "example", "example.sql"), "SELECT 1;")
+	writeTestFile(t, filepath.Join(dagsFolder, "example.py"), "dag")
+
+	err := execFlowCmd("clean", "--project-dir", projectDir, "--dags")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dagsFolder, "example.py"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFlowCleanCmdRefusesProjectDir(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "project")
+	writeTestFile(t, filepath.Join(projectDir, "workflows", "example", "example.sql"), "SELECT 1;")
+
+	for _, dataDirectory := range []string{projectDir, filepath.Dir(projectDir)} {
+		patchConfigKeyDirs(t, filepath.Join(projectDir, ".airflow", "dags"), dataDirectory)
+		err := execFlowCmd("clean", "--project-dir", projectDir, "--data")
+		assert.ErrorIs(t, err, errCleanProjectDir)
+		_, err = os.Stat(filepath.Join(projectDir, "workflows", "example", "example.sql"))
+		assert.NoError(t, err)
+	}
+}
+
+func TestFlowCleanCmdForce(t *testing.T) {
+	projectDir := t.TempDir()
+	dataDirectory := t.TempDir()
+	patchConfigKeyDirs(t, t.TempDir(), dataDirectory)
+	writeTestFile(t, filepath.Join(dataDirectory, "example.db"), "data")
+
+	buf := new(bytes.Buffer)
+	cmd := NewFlowCommand(&httputil.TransportConfig{})
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"clean", "--project-dir", projectDir, "--data", "--force"})
+	_, err := cmd.ExecuteC()
+	assert.NoError(t, err)
+	_, err = os.Stat(dataDirectory)
+	assert.True(t, os.IsNotExist(err))
+	assert.NotContains(t, buf.String(), "The following paths will be removed")
+	assert.Contains(t, buf.String(), "Removed "+dataDirectory)
+}
+
+func TestFlowCleanCmdImageNotBuilt(t *testing.T) {
+	projectDir := t.TempDir()
+	patchImageExists(t, false)
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		t.Error("the flow image must not be built to locate the DAGs and data")
+		return mountDirs, nil
+	}
+	t.Cleanup(func() { appendConfigKeyMountDir = originalAppendConfigKeyMountDir })
+	writeTestFile(t, filepath.Join(projectDir, "workflows", "example", "example.sql"), "SELECT 1;")
+
+	buf := new(bytes.Buffer)
+	cmd := NewFlowCommand(&httputil.TransportConfig{})
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"clean", "--project-dir", projectDir, "--dags", "--data"})
+	_, err := cmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Skipping the generated DAGs and data")
+}
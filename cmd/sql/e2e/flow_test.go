@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	sql "github.com/astronomer/astro-cli/cmd/sql"
+	"github.com/astronomer/astro-cli/pkg/httputil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,7 +31,7 @@ func chdir(t *testing.T, dir string) func() {
 }
 
 func execFlowCmd(args ...string) error {
-	cmd := sql.NewFlowCommand()
+	cmd := sql.NewFlowCommand(&httputil.TransportConfig{})
 	cmd.SetArgs(args)
 	_, err := cmd.ExecuteC()
 	return err
@@ -2,10 +2,14 @@ package sql
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/astronomer/astro-cli/pkg/httputil"
+	"github.com/astronomer/astro-cli/pkg/printutil"
 	"github.com/astronomer/astro-cli/sql"
 	"github.com/spf13/cobra"
 )
@@ -21,11 +25,14 @@ var (
 	noGenerateTasks   bool
 	verbose           bool
 	debug             bool
+	artifactsDir      string
+	templateName      string
 )
 
 var (
 	configCommandString = []string{"config"}
 	globalConfigKeys    = []string{"airflow_home", "airflow_dags_folder", "data_dir"}
+	dagsFolderConfigKey = "airflow_dags_folder"
 )
 
 func getAbsolutePath(path string) (string, error) {
@@ -65,6 +72,8 @@ func getBaseMountDirs(projectDir string) ([]string, error) {
 	return mountDirs, nil
 }
 
+var resolveTemplate = sql.ResolveTemplate
+
 var appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
 	args := []string{configKey}
 	exitCode, output, err := sql.ExecuteCmdInDocker(configCommandString, args, configFlags, mountDirs, true)
@@ -82,17 +91,19 @@ var appendConfigKeyMountDir = func(configKey string, configFlags map[string]stri
 	return mountDirs, nil
 }
 
-func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, setAirflowDagsFolder, setDataDir, mountGlobalDirs bool) (flags map[string]string, mountDirs []string, err error) {
+// buildFlagsAndMountDirs returns the flags and the directories to mount to run a flow command on projectDir.
+// The airflow dags folder is only resolved, and returned, when the global dirs are mounted.
+func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, setAirflowDagsFolder, setDataDir, mountGlobalDirs bool) (flags map[string]string, mountDirs []string, dagsFolder string, err error) {
 	flags = make(map[string]string)
 	mountDirs, err = getBaseMountDirs(projectDir)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 
 	if setProjectDir {
 		projectDir, err = getAbsolutePath(projectDir)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		flags["project-dir"] = projectDir
 	}
@@ -103,7 +114,10 @@ func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, se
 		for _, globalConfigKey := range globalConfigKeys {
 			mountDirs, err = appendConfigKeyMountDir(globalConfigKey, configFlags, mountDirs)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, "", err
+			}
+			if globalConfigKey == dagsFolderConfigKey {
+				dagsFolder = mountDirs[len(mountDirs)-1]
 			}
 		}
 	}
@@ -111,7 +125,7 @@ func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, se
 	if setAirflowHome && airflowHome != "" {
 		airflowHomeAbs, err := getAbsolutePath(airflowHome)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		flags["airflow-home"] = airflowHomeAbs
 		mountDirs = append(mountDirs, airflowHomeAbs)
@@ -120,7 +134,7 @@ func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, se
 	if setAirflowDagsFolder && airflowDagsFolder != "" {
 		airflowDagsFolderAbs, err := getAbsolutePath(airflowDagsFolder)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		flags["airflow-dags-folder"] = airflowDagsFolderAbs
 		mountDirs = append(mountDirs, airflowDagsFolderAbs)
@@ -129,20 +143,24 @@ func buildFlagsAndMountDirs(projectDir string, setProjectDir, setAirflowHome, se
 	if setDataDir && dataDir != "" {
 		dataDirAbs, err := getAbsolutePath(dataDir)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, "", err
 		}
 		flags["data-dir"] = dataDirAbs
 		mountDirs = append(mountDirs, dataDirAbs)
 	}
 
-	return flags, mountDirs, nil
+	return flags, mountDirs, dagsFolder, nil
 }
 
-func executeCmd(cmd *cobra.Command, args []string, flags map[string]string, mountDirs []string) error {
-	cmdString := []string{cmd.Name()}
+func getCmdString(cmd *cobra.Command) []string {
 	if debug {
-		cmdString = []string{"--debug", cmd.Name()}
+		return []string{"--debug", cmd.Name()}
 	}
+	return []string{cmd.Name()}
+}
+
+func executeCmd(cmd *cobra.Command, args []string, flags map[string]string, mountDirs []string) error {
+	cmdString := getCmdString(cmd)
 	exitCode, _, err := sql.ExecuteCmdInDocker(cmdString, args, flags, mountDirs, false)
 	if err != nil {
 		return fmt.Errorf("error running %v: %w", cmdString, err)
@@ -154,8 +172,106 @@ func executeCmd(cmd *cobra.Command, args []string, flags map[string]string, moun
 	return nil
 }
 
+// executeCmdWithArtifacts runs the command like executeCmd. The container logs are captured when
+// --artifacts-dir is set, to copy them with the run status and the given DAG files into the artifacts directory,
+// and when profile is not nil, to record the task durations.
+func executeCmdWithArtifacts(cmd *cobra.Command, args []string, flags map[string]string, mountDirs, dagFiles []string, profile *sql.Profile) error {
+	cmdString := getCmdString(cmd)
+	switch {
+	case artifactsDir != "":
+		artifactsDirAbs, err := getAbsolutePath(artifactsDir)
+		if err != nil {
+			return err
+		}
+		collector, err := executeCmdCollectingArtifacts(cmdString, args, flags, mountDirs, dagFiles, artifactsDirAbs, os.Stdout, profile)
+		if collector != nil {
+			fmt.Printf("Artifacts written to %s\n", artifactsDirAbs)
+		}
+		return err
+	case profile != nil:
+		_, _, err := executeCmdCapturingLogs(cmdString, args, flags, mountDirs, os.Stdout, profile)
+		return err
+	default:
+		return executeCmd(cmd, args, flags, mountDirs)
+	}
+}
+
+// executeCmdCapturingLogs runs the flow command in docker, forwards its logs to out and returns them with the run status.
+// The phases and the task durations of the command are recorded into profile, if not nil.
+func executeCmdCapturingLogs(cmdString, args []string, flags map[string]string, mountDirs []string, out io.Writer, profile *sql.Profile) (*sql.RunStatus, string, error) {
+	status := sql.RunStatus{Command: append(append([]string{}, cmdString...), args...), StartedAt: time.Now().UTC()}
+	var exitCode int64
+	var output io.ReadCloser
+	var cmdErr error
+	if profile != nil {
+		exitCode, output, cmdErr = sql.ExecuteCmdInDockerWithProfile(profile, cmdString, args, flags, mountDirs, true)
+	} else {
+		exitCode, output, cmdErr = sql.ExecuteCmdInDocker(cmdString, args, flags, mountDirs, true)
+	}
+	status.FinishedAt = time.Now().UTC()
+	status.ExitCode = exitCode
+
+	logs := new(strings.Builder)
+	if output != nil {
+		if _, err := sql.Io().Copy(io.MultiWriter(out, logs), output); err != nil && cmdErr == nil {
+			cmdErr = fmt.Errorf("docker logs forwarding failed %w", err)
+		}
+	}
+
+	if cmdErr != nil {
+		cmdErr = fmt.Errorf("error running %v: %w", cmdString, cmdErr)
+	} else if exitCode != 0 {
+		cmdErr = sql.DockerNonZeroExitCodeError(exitCode)
+	}
+	status.Success = cmdErr == nil
+	if cmdErr != nil {
+		status.Error = cmdErr.Error()
+	}
+	profile.RecordTaskDurations(logs.String())
+
+	return &status, logs.String(), cmdErr
+}
+
+// executeCmdCollectingArtifacts runs the flow command in docker like executeCmdCapturingLogs and
+// writes the logs, the run status and the given DAG files into artifactsDir
+func executeCmdCollectingArtifacts(cmdString, args []string, flags map[string]string, mountDirs, dagFiles []string, artifactsDir string, out io.Writer, profile *sql.Profile) (*sql.ArtifactsCollector, error) {
+	collector, err := sql.NewArtifactsCollector(artifactsDir, cmdString[len(cmdString)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	status, logs, cmdErr := executeCmdCapturingLogs(cmdString, args, flags, mountDirs, out, profile)
+	if err := collector.AddContent(sql.ArtifactKindLog, sql.ArtifactsLogFileName, []byte(logs)); err != nil {
+		return nil, err
+	}
+	if err := collector.AddStatus(status); err != nil {
+		return nil, err
+	}
+	for _, dagFile := range dagFiles {
+		if _, err := os.Stat(dagFile); err != nil {
+			// nothing was generated, e.g. because the command failed
+			continue
+		}
+		if err := collector.AddFile(sql.ArtifactKindDAG, dagFile); err != nil {
+			return nil, err
+		}
+	}
+	if err := collector.WriteManifest(); err != nil {
+		return nil, err
+	}
+
+	return collector, cmdErr
+}
+
+func getWorkflowDagFiles(workflowName, dagsFolder string) []string {
+	if dagsFolder == "" {
+		return nil
+	}
+	return []string{filepath.Join(dagsFolder, workflowName+".py")}
+}
+
 func executeBase(cmd *cobra.Command, args []string) error {
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, false, false, false, false, false)
+	flags, mountDirs, _, err := buildFlagsAndMountDirs(projectDir, false, false, false, false, false)
 	if err != nil {
 		return err
 	}
@@ -167,7 +283,7 @@ func executeInit(cmd *cobra.Command, args []string) error {
 		projectDir = args[0]
 	}
 
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, false, true, true, true, false)
+	flags, mountDirs, _, err := buildFlagsAndMountDirs(projectDir, false, true, true, true, false)
 	if err != nil {
 		return err
 	}
@@ -175,7 +291,21 @@ func executeInit(cmd *cobra.Command, args []string) error {
 	projectDirAbsolute := mountDirs[0]
 	args = []string{projectDirAbsolute}
 
-	return executeCmd(cmd, args, flags, mountDirs)
+	template, err := resolveTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	if err := executeCmd(cmd, args, flags, mountDirs); err != nil {
+		return err
+	}
+
+	// the image generates the files matching its SQL CLI version, the template only adds the missing ones
+	existingFiles, err := sql.ExistingTemplateFiles(template, projectDirAbsolute)
+	if err != nil {
+		return err
+	}
+	return sql.CopyTemplate(template, projectDirAbsolute, existingFiles)
 }
 
 func executeConfig(cmd *cobra.Command, args []string) error {
@@ -183,7 +313,7 @@ func executeConfig(cmd *cobra.Command, args []string) error {
 		return sql.ArgNotSetError("key")
 	}
 
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, false)
+	flags, mountDirs, _, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, false)
 	if err != nil {
 		return err
 	}
@@ -200,7 +330,7 @@ func executeValidate(cmd *cobra.Command, args []string) error {
 		projectDir = args[0]
 	}
 
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, false, false, false, false, false)
+	flags, mountDirs, _, err := buildFlagsAndMountDirs(projectDir, false, false, false, false, false)
 	if err != nil {
 		return err
 	}
@@ -220,7 +350,7 @@ func executeValidate(cmd *cobra.Command, args []string) error {
 		args = append(args, "--verbose")
 	}
 
-	return executeCmd(cmd, args, flags, mountDirs)
+	return executeCmdWithArtifacts(cmd, args, flags, mountDirs, nil, nil)
 }
 
 func executeGenerate(cmd *cobra.Command, args []string) error {
@@ -228,7 +358,7 @@ func executeGenerate(cmd *cobra.Command, args []string) error {
 		return sql.ArgNotSetError("workflow_name")
 	}
 
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, true)
+	flags, mountDirs, dagsFolder, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, true)
 	if err != nil {
 		return err
 	}
@@ -248,7 +378,7 @@ func executeGenerate(cmd *cobra.Command, args []string) error {
 		args = append(args, "--verbose")
 	}
 
-	return executeCmd(cmd, args, flags, mountDirs)
+	return executeCmdWithArtifacts(cmd, args, flags, mountDirs, getWorkflowDagFiles(args[0], dagsFolder), nil)
 }
 
 func executeRun(cmd *cobra.Command, args []string) error {
@@ -256,7 +386,7 @@ func executeRun(cmd *cobra.Command, args []string) error {
 		return sql.ArgNotSetError("workflow_name")
 	}
 
-	flags, mountDirs, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, true)
+	flags, mountDirs, dagsFolder, err := buildFlagsAndMountDirs(projectDir, true, false, false, false, true)
 	if err != nil {
 		return err
 	}
@@ -265,7 +395,8 @@ func executeRun(cmd *cobra.Command, args []string) error {
 		flags["env"] = environment
 	}
 
-	if verbose {
+	// the task durations of a profile are read from the verbose logs
+	if verbose || profileRun {
 		args = append(args, "--verbose")
 	}
 
@@ -276,7 +407,44 @@ func executeRun(cmd *cobra.Command, args []string) error {
 		args = append(args, "--no-generate-tasks")
 	}
 
-	return executeCmd(cmd, args, flags, mountDirs)
+	if profileRun {
+		return executeCmdWithProfile(cmd, args, flags, mountDirs, getWorkflowDagFiles(args[0], dagsFolder))
+	}
+	return executeCmdWithArtifacts(cmd, args, flags, mountDirs, getWorkflowDagFiles(args[0], dagsFolder), nil)
+}
+
+func executeTemplatesList(cmd *cobra.Command, args []string) error {
+	templates, err := sql.ListTemplates()
+	if err != nil {
+		return err
+	}
+	tab := printutil.Table{
+		Padding:        []int{30, 20, 10},
+		DynamicPadding: true,
+		Header:         []string{"NAME", "VERSION", "SOURCE"},
+		NoResultsMsg:   "No templates found",
+	}
+	for _, template := range templates {
+		tab.AddRow([]string{template.Name, template.Version, template.Source}, false)
+	}
+	return tab.Print(cmd.OutOrStdout())
+}
+
+func executeTemplatesUpdate(cmd *cobra.Command, args []string) error {
+	bundles, err := sql.UpdateTemplates(sql.AstroSQLCLITemplatesIndexURL)
+	if err != nil {
+		return err
+	}
+	for _, bundle := range bundles {
+		fmt.Fprintf(cmd.OutOrStdout(), "template %s %s verified and cached\n", bundle.Name, bundle.Version)
+	}
+	return nil
+}
+
+// executeLocalHelp prints the usage of commands implemented by the CLI itself instead of the flow container
+func executeLocalHelp(cmd *cobra.Command, args []string) {
+	fmt.Fprintln(cmd.OutOrStdout(), cmd.Short)
+	_ = cmd.Usage()
 }
 
 func executeHelp(cmd *cobra.Command, cmdString []string) {
@@ -322,6 +490,43 @@ func initCommand() *cobra.Command {
 	cmd.Flags().StringVar(&airflowHome, "airflow-home", "", "")
 	cmd.Flags().StringVar(&airflowDagsFolder, "airflow-dags-folder", "", "")
 	cmd.Flags().StringVar(&dataDir, "data-dir", "", "")
+	cmd.Flags().StringVar(&templateName, "template", sql.DefaultTemplateName, "")
+	return cmd
+}
+
+func templatesListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the flow project templates available to init",
+		Args:         cobra.NoArgs,
+		RunE:         executeTemplatesList,
+		SilenceUsage: true,
+	}
+	cmd.SetHelpFunc(executeLocalHelp)
+	return cmd
+}
+
+func templatesUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "update",
+		Short:        "Download and verify the latest flow project templates",
+		Args:         cobra.NoArgs,
+		RunE:         executeTemplatesUpdate,
+		SilenceUsage: true,
+	}
+	cmd.SetHelpFunc(executeLocalHelp)
+	return cmd
+}
+
+func templatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "templates",
+		Short:        "Manage the flow project templates",
+		SilenceUsage: true,
+	}
+	cmd.SetHelpFunc(executeLocalHelp)
+	cmd.AddCommand(templatesListCommand())
+	cmd.AddCommand(templatesUpdateCommand())
 	return cmd
 }
 
@@ -349,6 +554,7 @@ func validateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&environment, "env", "default", "")
 	cmd.Flags().StringVar(&connection, "connection", "", "")
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "")
+	cmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "")
 	return cmd
 }
 
@@ -366,6 +572,7 @@ func generateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&environment, "env", "default", "")
 	cmd.Flags().StringVar(&projectDir, "project-dir", ".", "")
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "")
+	cmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "")
 	cmd.MarkFlagsMutuallyExclusive("generate-tasks", "no-generate-tasks")
 	return cmd
 }
@@ -384,6 +591,9 @@ func runCommand() *cobra.Command {
 	cmd.Flags().StringVar(&environment, "env", "default", "")
 	cmd.Flags().StringVar(&projectDir, "project-dir", ".", "")
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "")
+	cmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "")
+	cmd.Flags().BoolVar(&profileRun, "profile", false, "")
+	cmd.Flags().StringVar(&profileFile, "profile-file", defaultProfileFile, "")
 	cmd.MarkFlagsMutuallyExclusive("generate-tasks", "no-generate-tasks")
 	return cmd
 }
@@ -393,7 +603,8 @@ func login(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func NewFlowCommand() *cobra.Command {
+func NewFlowCommand(transportConfig *httputil.TransportConfig) *cobra.Command {
+	sql.Transport = transportConfig
 	cmd := &cobra.Command{
 		Use:               "flow",
 		Short:             "Run flow commands",
@@ -410,5 +621,8 @@ func NewFlowCommand() *cobra.Command {
 	cmd.AddCommand(validateCommand())
 	cmd.AddCommand(generateCommand())
 	cmd.AddCommand(runCommand())
+	cmd.AddCommand(templatesCommand())
+	cmd.AddCommand(serveCommand())
+	cmd.AddCommand(cleanCommand())
 	return cmd
 }
@@ -1,13 +1,17 @@
 package sql
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	sql "github.com/astronomer/astro-cli/sql"
 	"github.com/astronomer/astro-cli/sql/mocks"
 	"github.com/docker/docker/api/types"
@@ -113,7 +117,7 @@ func chdir(t *testing.T, dir string) func() {
 }
 
 func execFlowCmd(args ...string) error {
-	cmd := NewFlowCommand()
+	cmd := NewFlowCommand(&httputil.TransportConfig{})
 	cmd.SetArgs(args)
 	_, err := cmd.ExecuteC()
 	return err
@@ -333,8 +337,171 @@ func TestBuildFlagsAndMountDirsFailures(t *testing.T) {
 	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
 
 	appendConfigKeyMountDir = mockAppendConfigKeyMountDirErr
-	_, _, err := buildFlagsAndMountDirs("", false, false, false, false, true)
+	_, _, _, err := buildFlagsAndMountDirs("", false, false, false, false, true)
 	assert.EqualError(t, err, "mock error")
 
 	appendConfigKeyMountDir = originalAppendConfigKeyMountDir
 }
+
+func TestBuildFlagsAndMountDirsDagsFolder(t *testing.T) {
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	defer func() { appendConfigKeyMountDir = originalAppendConfigKeyMountDir }()
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		return append(mountDirs, "/"+configKey), nil
+	}
+
+	_, mountDirs, dagsFolder, err := buildFlagsAndMountDirs(t.TempDir(), true, false, false, false, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "/"+dagsFolderConfigKey, dagsFolder)
+	assert.Contains(t, mountDirs, dagsFolder)
+
+	_, _, dagsFolder, err = buildFlagsAndMountDirs(t.TempDir(), true, false, false, false, false)
+	assert.NoError(t, err)
+	assert.Empty(t, dagsFolder)
+}
+
+func TestFlowGenerateCmdWithArtifactsDir(t *testing.T) {
+	originalExecuteCmdInDocker := sql.ExecuteCmdInDocker
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	defer func() {
+		sql.ExecuteCmdInDocker = originalExecuteCmdInDocker
+		appendConfigKeyMountDir = originalAppendConfigKeyMountDir
+	}()
+
+	projectDir := t.TempDir()
+	dagsFolder := t.TempDir()
+	artifactsDir := filepath.Join(t.TempDir(), "artifacts")
+	err := os.WriteFile(filepath.Join(dagsFolder, "example_basic_transform.py"), []byte("dag"), 0o600)
+	assert.NoError(t, err)
+
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		if configKey == dagsFolderConfigKey {
+			return append(mountDirs, dagsFolder), nil
+		}
+		return append(mountDirs, t.TempDir()), nil
+	}
+	sql.ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (exitCode int64, output io.ReadCloser, err error) {
+		assert.True(t, returnOutput)
+		return 0, io.NopCloser(strings.NewReader("Sample log")), nil
+	}
+
+	err = execFlowCmd("generate", "example_basic_transform", "--project-dir", projectDir, "--artifacts-dir", artifactsDir)
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(artifactsDir, sql.ArtifactsManifestFileName))
+	assert.NoError(t, err)
+	var manifest sql.ArtifactsManifest
+	assert.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, "generate", manifest.Command)
+	paths := []string{}
+	for _, artifact := range manifest.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	assert.Equal(t, []string{sql.ArtifactsLogFileName, sql.ArtifactsStatusFileName, "dags/example_basic_transform.py"}, paths)
+
+	logs, err := os.ReadFile(filepath.Join(artifactsDir, sql.ArtifactsLogFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sample log", string(logs))
+}
+
+func TestFlowValidateCmdWithArtifactsDirNonZeroExitCode(t *testing.T) {
+	originalExecuteCmdInDocker := sql.ExecuteCmdInDocker
+	defer func() { sql.ExecuteCmdInDocker = originalExecuteCmdInDocker }()
+
+	projectDir := t.TempDir()
+	artifactsDir := t.TempDir()
+	sql.ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (exitCode int64, output io.ReadCloser, err error) {
+		return 1, io.NopCloser(strings.NewReader("validation failed")), nil
+	}
+
+	err := execFlowCmd("validate", projectDir, "--artifacts-dir", artifactsDir)
+	assert.EqualError(t, err, "docker command has returned a non-zero exit code:1")
+
+	data, err := os.ReadFile(filepath.Join(artifactsDir, sql.ArtifactsStatusFileName))
+	assert.NoError(t, err)
+	var status sql.RunStatus
+	assert.NoError(t, json.Unmarshal(data, &status))
+	assert.Equal(t, int64(1), status.ExitCode)
+	assert.False(t, status.Success)
+	assert.Equal(t, "docker command has returned a non-zero exit code:1", status.Error)
+}
+
+func TestFlowTemplatesListCmd(t *testing.T) {
+	originalTemplatesCacheDir := sql.TemplatesCacheDir
+	sql.TemplatesCacheDir = t.TempDir()
+	defer func() { sql.TemplatesCacheDir = originalTemplatesCacheDir }()
+
+	buf := new(bytes.Buffer)
+	cmd := NewFlowCommand(&httputil.TransportConfig{})
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"templates", "list"})
+	_, err := cmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "default")
+	assert.Contains(t, buf.String(), sql.TemplateSourceEmbedded)
+}
+
+func TestFlowTemplatesHelpCmd(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := NewFlowCommand(&httputil.TransportConfig{})
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"templates", "--help"})
+	_, err := cmd.ExecuteC()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Manage the flow project templates")
+}
+
+// patchResolveTemplate resolves templates from the cache or the embedded copies, without fetching the templates index
+func patchResolveTemplate(t *testing.T) {
+	originalResolveTemplate := resolveTemplate
+	originalTemplatesCacheDir := sql.TemplatesCacheDir
+	resolveTemplate = sql.GetTemplate
+	sql.TemplatesCacheDir = t.TempDir()
+	t.Cleanup(func() {
+		resolveTemplate = originalResolveTemplate
+		sql.TemplatesCacheDir = originalTemplatesCacheDir
+	})
+}
+
+func TestFlowInitCmdCopiesTemplate(t *testing.T) {
+	patchResolveTemplate(t)
+	originalExecuteCmdInDocker := sql.ExecuteCmdInDocker
+	sql.ExecuteCmdInDocker = mockExecuteCmdInDockerReturnSuccess
+	defer func() { sql.ExecuteCmdInDocker = originalExecuteCmdInDocker }()
+
+	projectDir := t.TempDir()
+	err := execFlowCmd("init", projectDir, "--template", sql.DefaultTemplateName)
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(projectDir, "workflows", "example_basic_transform", "top_animations.sql"))
+	assert.NoError(t, err)
+}
+
+func TestFlowInitCmdKeepsGeneratedFiles(t *testing.T) {
+	patchResolveTemplate(t)
+	originalExecuteCmdInDocker := sql.ExecuteCmdInDocker
+	sql.ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (int64, io.ReadCloser, error) {
+		// the image writes the configuration of the SQL CLI version it runs
+		configFile := filepath.Join(args[0], "config", "default", "configuration.yml")
+		if err := os.MkdirAll(filepath.Dir(configFile), 0o755); err != nil {
+			return 1, nil, err
+		}
+		return 0, nil, os.WriteFile(configFile, []byte("generated by the image"), 0o600)
+	}
+	defer func() { sql.ExecuteCmdInDocker = originalExecuteCmdInDocker }()
+
+	projectDir := t.TempDir()
+	err := execFlowCmd("init", projectDir, "--template", sql.DefaultTemplateName)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(projectDir, "config", "default", "configuration.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "generated by the image", string(content))
+	_, err = os.Stat(filepath.Join(projectDir, "workflows", "example_basic_transform", "top_animations.sql"))
+	assert.NoError(t, err)
+}
+
+func TestFlowInitCmdTemplateNotFound(t *testing.T) {
+	patchResolveTemplate(t)
+
+	err := execFlowCmd("init", t.TempDir(), "--template", "unknown")
+	assert.EqualError(t, err, "template not found:unknown")
+}
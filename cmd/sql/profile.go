@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/astronomer/astro-cli/pkg/printutil"
+	"github.com/astronomer/astro-cli/sql"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultProfileFile = "flow_profile.json"
+	percent            = 100
+)
+
+var (
+	profileRun  bool
+	profileFile string
+)
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.2fs", seconds)
+}
+
+// executeCmdWithProfile runs the command like executeCmdWithArtifacts, then writes the time spent
+// in each phase and task to --profile-file and prints a summary of it.
+// --profile implies --verbose, as the task durations are only logged in verbose mode.
+func executeCmdWithProfile(cmd *cobra.Command, args []string, flags map[string]string, mountDirs, dagFiles []string) error {
+	profileFileAbs, err := getAbsolutePath(profileFile)
+	if err != nil {
+		return err
+	}
+
+	profile := sql.StartProfile(append(getCmdString(cmd), args...))
+	cmdErr := executeCmdWithArtifacts(cmd, args, flags, mountDirs, dagFiles, profile)
+	profile.Stop(cmdErr == nil)
+
+	if err := profile.Write(profileFileAbs); err != nil {
+		return err
+	}
+	if err := printProfileSummary(profile, os.Stdout); err != nil {
+		return err
+	}
+	fmt.Printf("Profile written to %s\n", profileFileAbs)
+	return cmdErr
+}
+
+func printProfileSummary(profile *sql.Profile, out io.Writer) error {
+	fmt.Fprintf(out, "\nTotal time: %s", formatSeconds(profile.TotalSeconds))
+	if profile.SQLCLIVersion != "" {
+		fmt.Fprintf(out, " (SQL CLI %s)", profile.SQLCLIVersion)
+	}
+	fmt.Fprintln(out)
+
+	phases := printutil.Table{
+		Padding:        []int{20, 12, 8},
+		DynamicPadding: true,
+		Header:         []string{"PHASE", "DURATION", "SHARE"},
+		NoResultsMsg:   "No phases recorded",
+	}
+	for _, phase := range profile.Phases {
+		share := 0.0
+		if profile.TotalSeconds > 0 {
+			share = phase.Seconds / profile.TotalSeconds * percent
+		}
+		phases.AddRow([]string{phase.Name, formatSeconds(phase.Seconds), fmt.Sprintf("%.0f%%", share)}, false)
+	}
+	if err := phases.Print(out); err != nil {
+		return err
+	}
+
+	if len(profile.Tasks) == 0 {
+		fmt.Fprintln(out, "No task durations found in the logs")
+		return nil
+	}
+	tasks := printutil.Table{
+		Padding:        []int{30, 30, 10, 12},
+		DynamicPadding: true,
+		Header:         []string{"DAG", "TASK", "STATE", "DURATION"},
+	}
+	for _, task := range profile.Tasks {
+		tasks.AddRow([]string{task.DagID, task.TaskID, task.State, formatSeconds(task.Seconds)}, false)
+	}
+	return tasks.Print(out)
+}
@@ -0,0 +1,73 @@
+package sql
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sql "github.com/astronomer/astro-cli/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTaskLog = "INFO - Marking task as SUCCESS. dag_id=example_basic_transform, task_id=top_animations, " +
+	"execution_date=20230101T000000, start_date=20230405T101112, end_date=20230405T101115\n"
+
+func patchProfileExecution(t *testing.T, exitCode int64) {
+	originalExecuteCmdInDockerWithProfile := sql.ExecuteCmdInDockerWithProfile
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	sql.ExecuteCmdInDockerWithProfile = func(profile *sql.Profile, cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (int64, io.ReadCloser, error) {
+		assert.NotNil(t, profile)
+		assert.True(t, returnOutput)
+		assert.Contains(t, args, "--verbose")
+		profile.TrackPhase(sql.PhaseCLIExecution)()
+		return exitCode, io.NopCloser(strings.NewReader(testTaskLog)), nil
+	}
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		return append(mountDirs, t.TempDir()), nil
+	}
+	t.Cleanup(func() {
+		sql.ExecuteCmdInDockerWithProfile = originalExecuteCmdInDockerWithProfile
+		appendConfigKeyMountDir = originalAppendConfigKeyMountDir
+	})
+}
+
+func readProfile(t *testing.T, path string) *sql.Profile {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	profile := &sql.Profile{}
+	assert.NoError(t, json.Unmarshal(data, profile))
+	return profile
+}
+
+func TestFlowRunCmdWithProfile(t *testing.T) {
+	patchProfileExecution(t, 0)
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+
+	err := execFlowCmd("run", "example_basic_transform", "--project-dir", t.TempDir(), "--profile", "--profile-file", profilePath)
+	assert.NoError(t, err)
+
+	profile := readProfile(t, profilePath)
+	assert.True(t, profile.Success)
+	assert.Equal(t, []string{"run", "example_basic_transform", "--verbose"}, profile.Command)
+	assert.Len(t, profile.Phases, 1)
+	assert.Equal(t, []sql.TaskTiming{{DagID: "example_basic_transform", TaskID: "top_animations", State: "SUCCESS", Seconds: 3}}, profile.Tasks)
+}
+
+func TestFlowRunCmdWithProfileNonZeroExitCode(t *testing.T) {
+	patchProfileExecution(t, 1)
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+
+	err := execFlowCmd("run", "example_basic_transform", "--project-dir", t.TempDir(), "--profile", "--profile-file", profilePath)
+	assert.EqualError(t, err, "docker command has returned a non-zero exit code:1")
+
+	profile := readProfile(t, profilePath)
+	assert.False(t, profile.Success)
+}
+
+func TestFlowGenerateCmdProfileNotSupported(t *testing.T) {
+	err := execFlowCmd("generate", "example_basic_transform", "--project-dir", t.TempDir(), "--profile")
+	assert.EqualError(t, err, "unknown flag: --profile")
+}
@@ -0,0 +1,398 @@
+package sql
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/astronomer/astro-cli/sql"
+	"github.com/lucsky/cuid"
+	"github.com/spf13/cobra"
+)
+
+const (
+	serveTokenEnvVar         = "ASTRO_FLOW_SERVE_TOKEN"
+	serveMaxProjectSize      = 100 << 20
+	serveMaxExtractedSize    = 1 << 30
+	serveReadHeaderTimeout   = 10 * time.Second
+	servePruneInterval       = 10 * time.Minute
+	serveShutdownTimeout     = 30 * time.Second
+	serveWorkDirMode         = 0o755
+	flowRunStatusRunning     = "running"
+	flowRunStatusSucceeded   = "succeeded"
+	flowRunStatusFailed      = "failed"
+	defaultServeHost         = "127.0.0.1"
+	defaultServePort         = 8080
+	defaultMaxConcurrentRuns = 2
+	defaultServeRetention    = 24 * time.Hour
+)
+
+var (
+	serveHost              string
+	servePort              int
+	serveToken             string
+	serveWorkDir           string
+	serveMaxConcurrentRuns int
+	serveRetention         time.Duration
+
+	errServeTokenNotSet         = errors.New("a token is required to serve flow, set it with --token or " + serveTokenEnvVar)
+	errInvalidMaxConcurrentRuns = errors.New("--max-concurrent-runs must be at least 1")
+	errInvalidRetention         = errors.New("--retention must be positive")
+	errMountDirOutsideRunDir    = errors.New("the project configuration points to a directory outside of the request directory")
+	errServeShutdownTimeout     = errors.New("timed out waiting for the running commands to finish, their containers may be left behind")
+)
+
+// flowRun is the state of a command submitted to the flow server
+type flowRun struct {
+	ID         string         `json:"id"`
+	Command    string         `json:"command"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Logs       string         `json:"logs,omitempty"`
+	Artifacts  []sql.Artifact `json:"artifacts,omitempty"`
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt *time.Time     `json:"finishedAt,omitempty"`
+}
+
+// flowServer exposes validate, generate and run over HTTP. Every request gets its own
+// project directory under workDir, extracted from the gzipped tarball sent as request body.
+// Finished runs and their artifacts are kept for retention, then pruned.
+type flowServer struct {
+	token          string
+	workDir        string
+	retention      time.Duration
+	maxProjectSize int64
+	slots          chan struct{}
+
+	mu   sync.Mutex
+	runs map[string]*flowRun
+	wg   sync.WaitGroup
+}
+
+func newFlowServer(token, workDir string, maxConcurrentRuns int, retention time.Duration) *flowServer {
+	return &flowServer{
+		token:          token,
+		workDir:        workDir,
+		retention:      retention,
+		maxProjectSize: serveMaxProjectSize,
+		slots:          make(chan struct{}, maxConcurrentRuns),
+		runs:           map[string]*flowRun{},
+	}
+}
+
+// wait waits for the running commands to finish, giving up when ctx is done
+func (s *flowServer) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errServeShutdownTimeout
+	}
+}
+
+// prune forgets the runs which finished more than retention before now and removes their directory
+func (s *flowServer) prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, run := range s.runs {
+		if run.FinishedAt == nil || now.Sub(*run.FinishedAt) < s.retention {
+			continue
+		}
+		delete(s.runs, id)
+		os.RemoveAll(filepath.Join(s.workDir, id))
+	}
+}
+
+func (s *flowServer) pruneEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.prune(now.UTC())
+		}
+	}
+}
+
+func (s *flowServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/v1/validate", s.authenticated(s.handleSubmit("validate")))
+	mux.Handle("/v1/generate", s.authenticated(s.handleSubmit("generate")))
+	mux.Handle("/v1/run", s.authenticated(s.handleSubmit("run")))
+	mux.Handle("/v1/status/", s.authenticated(http.HandlerFunc(s.handleStatus)))
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, map[string]string{"error": err.Error()})
+}
+
+func (s *flowServer) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeJSONError(w, http.StatusUnauthorized, errors.New("invalid token")) //nolint:goerr113
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *flowServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *flowServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)) //nolint:goerr113
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/v1/status/")
+	s.mu.Lock()
+	run, ok := s.runs[id]
+	var snapshot flowRun
+	if ok {
+		snapshot = *run
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("run %s not found", id)) //nolint:goerr113
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// handleSubmit extracts the project sent in the request body and starts the command in the background.
+// Query parameters mirror the CLI flags: workflow, env, connection and verbose.
+func (s *flowServer) handleSubmit(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)) //nolint:goerr113
+			return
+		}
+		query := r.URL.Query()
+		workflow := query.Get("workflow")
+		if command != "validate" && workflow == "" {
+			writeJSONError(w, http.StatusBadRequest, sql.ArgNotSetError("workflow_name"))
+			return
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		default:
+			writeJSONError(w, http.StatusTooManyRequests, fmt.Errorf("the maximum of %d concurrent runs has been reached", cap(s.slots))) //nolint:goerr113
+			return
+		}
+
+		run := &flowRun{ID: cuid.New(), Command: command, Status: flowRunStatusRunning, StartedAt: time.Now().UTC()}
+		runDir := filepath.Join(s.workDir, run.ID)
+		projectDir := filepath.Join(runDir, "project")
+		if err := os.MkdirAll(projectDir, serveWorkDirMode); err != nil {
+			<-s.slots
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if err := sql.ExtractTarGz(http.MaxBytesReader(w, r.Body, s.maxProjectSize), projectDir, serveMaxExtractedSize); err != nil {
+			<-s.slots
+			os.RemoveAll(runDir)
+			statusCode := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				statusCode = http.StatusRequestEntityTooLarge
+			}
+			writeJSONError(w, statusCode, fmt.Errorf("error extracting project: %w", err))
+			return
+		}
+
+		verbose, _ := strconv.ParseBool(query.Get("verbose"))
+		options := flowRunOptions{
+			command:    command,
+			workflow:   workflow,
+			env:        query.Get("env"),
+			connection: query.Get("connection"),
+			verbose:    verbose,
+		}
+
+		s.mu.Lock()
+		s.runs[run.ID] = run
+		snapshot := *run
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() { <-s.slots }()
+			// the project is only needed while the command runs, the artifacts are kept for the status endpoint
+			defer os.RemoveAll(projectDir)
+			s.execute(run.ID, runDir, projectDir, filepath.Join(runDir, "artifacts"), &options)
+		}()
+
+		writeJSON(w, http.StatusAccepted, snapshot)
+	}
+}
+
+type flowRunOptions struct {
+	command    string
+	workflow   string
+	env        string
+	connection string
+	verbose    bool
+}
+
+// execute runs the command through the same execution layer as the CLI commands
+func (s *flowServer) execute(id, runDir, projectDir, artifactsDir string, options *flowRunOptions) {
+	logs := new(strings.Builder)
+	collector, err := executeFlowRun(runDir, projectDir, artifactsDir, options, logs)
+
+	finishedAt := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run := s.runs[id]
+	run.FinishedAt = &finishedAt
+	run.Logs = logs.String()
+	if collector != nil {
+		run.Artifacts = collector.Manifest().Artifacts
+	}
+	if err != nil {
+		run.Status = flowRunStatusFailed
+		run.Error = err.Error()
+		return
+	}
+	run.Status = flowRunStatusSucceeded
+}
+
+// executeFlowRun runs the command on the project uploaded in runDir.
+// The project configuration is sent by the API caller, so the directories it points to are only mounted when they are within runDir.
+func executeFlowRun(runDir, projectDir, artifactsDir string, options *flowRunOptions, logs *strings.Builder) (*sql.ArtifactsCollector, error) {
+	var args []string
+	var dagFiles []string
+	mountGlobalDirs := options.command != "validate"
+
+	flags, mountDirs, dagsFolder, err := buildFlagsAndMountDirs(projectDir, mountGlobalDirs, false, false, false, mountGlobalDirs)
+	if err != nil {
+		return nil, err
+	}
+	for _, mountDir := range mountDirs {
+		if !sql.IsWithinDir(filepath.Clean(mountDir), runDir) {
+			return nil, fmt.Errorf("%w: %s", errMountDirOutsideRunDir, mountDir)
+		}
+	}
+	if mountGlobalDirs {
+		args = []string{options.workflow}
+		dagFiles = getWorkflowDagFiles(options.workflow, dagsFolder)
+	} else {
+		args = []string{mountDirs[0]}
+		if options.connection != "" {
+			flags["connection"] = options.connection
+		}
+	}
+	if options.env != "" {
+		flags["env"] = options.env
+	}
+	if options.verbose {
+		args = append(args, "--verbose")
+	}
+
+	return executeCmdCollectingArtifacts([]string{options.command}, args, flags, mountDirs, dagFiles, artifactsDir, logs, nil)
+}
+
+func executeServe(cmd *cobra.Command, args []string) error {
+	token := serveToken
+	if token == "" {
+		token = os.Getenv(serveTokenEnvVar)
+	}
+	if token == "" {
+		return errServeTokenNotSet
+	}
+	if serveMaxConcurrentRuns < 1 {
+		return errInvalidMaxConcurrentRuns
+	}
+	if serveRetention <= 0 {
+		return errInvalidRetention
+	}
+
+	workDir, err := getAbsolutePath(serveWorkDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(workDir, serveWorkDirMode); err != nil {
+		return fmt.Errorf("error creating work directory %s: %w", workDir, err)
+	}
+
+	server := newFlowServer(token, workDir, serveMaxConcurrentRuns, serveRetention)
+	httpServer := &http.Server{
+		Addr:              net.JoinHostPort(serveHost, strconv.Itoa(servePort)),
+		Handler:           server.handler(),
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go server.pruneEvery(ctx, servePruneInterval)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+	fmt.Fprintf(cmd.OutOrStdout(), "Serving flow on %s\n", httpServer.Addr)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// stop accepting requests, then let the running commands finish so their containers are not left behind
+	fmt.Fprintln(cmd.OutOrStdout(), "Shutting down, waiting for the running commands to finish")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	err = httpServer.Shutdown(shutdownCtx)
+	if waitErr := server.wait(shutdownCtx); waitErr != nil {
+		return waitErr
+	}
+	return err
+}
+
+func serveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "serve",
+		Short:        "Serve validate, generate and run over HTTP",
+		Args:         cobra.NoArgs,
+		RunE:         executeServe,
+		SilenceUsage: true,
+	}
+	cmd.SetHelpFunc(executeLocalHelp)
+	cmd.Flags().StringVar(&serveHost, "host", defaultServeHost, "Address to listen on, e.g. 0.0.0.0 to accept requests from other hosts")
+	cmd.Flags().IntVar(&servePort, "port", defaultServePort, "Port to listen on")
+	cmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required by every API call, defaults to "+serveTokenEnvVar)
+	cmd.Flags().StringVar(&serveWorkDir, "work-dir", filepath.Join(os.TempDir(), "astro-flow-serve"), "Directory holding the per-request projects and artifacts")
+	cmd.Flags().IntVar(&serveMaxConcurrentRuns, "max-concurrent-runs", defaultMaxConcurrentRuns, "Maximum number of commands running at the same time")
+	cmd.Flags().DurationVar(&serveRetention, "retention", defaultServeRetention, "How long the status, logs and artifacts of a finished command are kept")
+	return cmd
+}
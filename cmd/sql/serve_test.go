@@ -0,0 +1,217 @@
+package sql
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sql "github.com/astronomer/astro-cli/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+const testServeToken = "test-token"
+
+func buildProjectArchive(t *testing.T) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	content := "SELECT 1;"
+	err := tw.WriteHeader(&tar.Header{Name: "workflows/example/example.sql", Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	assert.NoError(t, err)
+	_, err = tw.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gzw.Close())
+	return buf
+}
+
+func serveRequest(t *testing.T, handler http.Handler, method, target string, body io.Reader, token string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, target, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	resp := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec, resp
+}
+
+func patchServeExecution(t *testing.T, exitCode int64) {
+	originalExecuteCmdInDocker := sql.ExecuteCmdInDocker
+	originalAppendConfigKeyMountDir := appendConfigKeyMountDir
+	sql.ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (int64, io.ReadCloser, error) {
+		return exitCode, io.NopCloser(strings.NewReader("Sample log")), nil
+	}
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		return append(mountDirs, filepath.Join(configFlags["project-dir"], ".airflow", configKey)), nil
+	}
+	t.Cleanup(func() {
+		sql.ExecuteCmdInDocker = originalExecuteCmdInDocker
+		appendConfigKeyMountDir = originalAppendConfigKeyMountDir
+	})
+}
+
+func TestFlowServeHealth(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	rec, resp := serveRequest(t, server.handler(), http.MethodGet, "/health", http.NoBody, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", resp["status"])
+}
+
+func TestFlowServeUnauthorized(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	rec, _ := serveRequest(t, server.handler(), http.MethodPost, "/v1/validate", buildProjectArchive(t), "wrong-token")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestFlowServeRun(t *testing.T) {
+	patchServeExecution(t, 0)
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	handler := server.handler()
+
+	rec, resp := serveRequest(t, handler, http.MethodPost, "/v1/run?workflow=example&env=dev", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, flowRunStatusRunning, resp["status"])
+	server.wg.Wait()
+
+	rec, resp = serveRequest(t, handler, http.MethodGet, "/v1/status/"+resp["id"].(string), http.NoBody, testServeToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, flowRunStatusSucceeded, resp["status"])
+	assert.Contains(t, resp["logs"], "Sample log")
+	assert.NotContains(t, resp["logs"], "Artifacts written to")
+	assert.Len(t, resp["artifacts"], 2)
+}
+
+func TestFlowServeMountDirOutsideRunDir(t *testing.T) {
+	patchServeExecution(t, 0)
+	outsideDir := t.TempDir()
+	appendConfigKeyMountDir = func(configKey string, configFlags map[string]string, mountDirs []string) ([]string, error) {
+		return append(mountDirs, outsideDir), nil
+	}
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	handler := server.handler()
+
+	rec, resp := serveRequest(t, handler, http.MethodPost, "/v1/run?workflow=example&env=dev", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	server.wg.Wait()
+
+	_, resp = serveRequest(t, handler, http.MethodGet, "/v1/status/"+resp["id"].(string), http.NoBody, testServeToken)
+	assert.Equal(t, flowRunStatusFailed, resp["status"])
+	assert.Contains(t, resp["error"], errMountDirOutsideRunDir.Error())
+	assert.Contains(t, resp["error"], outsideDir)
+}
+
+func TestFlowServeValidateFailure(t *testing.T) {
+	patchServeExecution(t, 1)
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	handler := server.handler()
+
+	rec, resp := serveRequest(t, handler, http.MethodPost, "/v1/validate?connection=sqlite_conn", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	server.wg.Wait()
+
+	_, resp = serveRequest(t, handler, http.MethodGet, "/v1/status/"+resp["id"].(string), http.NoBody, testServeToken)
+	assert.Equal(t, flowRunStatusFailed, resp["status"])
+	assert.Equal(t, "docker command has returned a non-zero exit code:1", resp["error"])
+}
+
+func TestFlowServeWorkflowNotSet(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	rec, resp := serveRequest(t, server.handler(), http.MethodPost, "/v1/generate", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "argument not set:workflow_name", resp["error"])
+}
+
+func TestFlowServeInvalidProject(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	rec, _ := serveRequest(t, server.handler(), http.MethodPost, "/v1/validate", strings.NewReader("not a tarball"), testServeToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Len(t, server.slots, 0)
+}
+
+func TestFlowServeProjectTooLarge(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	server.maxProjectSize = 16
+	rec, _ := serveRequest(t, server.handler(), http.MethodPost, "/v1/validate", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Len(t, server.slots, 0)
+}
+
+func TestFlowServeWaitTimeout(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	server.wg.Add(1)
+	defer server.wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.wait(ctx), errServeShutdownTimeout)
+}
+
+func TestFlowServeConcurrentRunLimit(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	// occupy the only slot as if a run was in progress
+	server.slots <- struct{}{}
+	rec, _ := serveRequest(t, server.handler(), http.MethodPost, "/v1/validate", buildProjectArchive(t), testServeToken)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestFlowServeStatusNotFound(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	rec, _ := serveRequest(t, server.handler(), http.MethodGet, "/v1/status/unknown", http.NoBody, testServeToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFlowServeCmdTokenNotSet(t *testing.T) {
+	t.Setenv(serveTokenEnvVar, "")
+	err := execFlowCmd("serve")
+	assert.ErrorIs(t, err, errServeTokenNotSet)
+}
+
+func TestFlowServeCmdInvalidMaxConcurrentRuns(t *testing.T) {
+	err := execFlowCmd("serve", "--token", testServeToken, "--max-concurrent-runs", "0")
+	assert.ErrorIs(t, err, errInvalidMaxConcurrentRuns)
+}
+
+func TestFlowServeCmdInvalidRetention(t *testing.T) {
+	err := execFlowCmd("serve", "--token", testServeToken, "--retention", "0s")
+	assert.ErrorIs(t, err, errInvalidRetention)
+}
+
+func TestFlowServePrune(t *testing.T) {
+	patchServeExecution(t, 0)
+	workDir := t.TempDir()
+	server := newFlowServer(testServeToken, workDir, 1, time.Hour)
+	handler := server.handler()
+
+	_, resp := serveRequest(t, handler, http.MethodPost, "/v1/run?workflow=example", buildProjectArchive(t), testServeToken)
+	server.wg.Wait()
+	id := resp["id"].(string)
+	assert.DirExists(t, filepath.Join(workDir, id))
+
+	// still within the retention
+	server.prune(time.Now().UTC())
+	rec, _ := serveRequest(t, handler, http.MethodGet, "/v1/status/"+id, http.NoBody, testServeToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	server.prune(time.Now().UTC().Add(2 * time.Hour))
+	rec, _ = serveRequest(t, handler, http.MethodGet, "/v1/status/"+id, http.NoBody, testServeToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.NoDirExists(t, filepath.Join(workDir, id))
+}
+
+func TestFlowServePruneKeepsRunningRuns(t *testing.T) {
+	server := newFlowServer(testServeToken, t.TempDir(), 1, time.Hour)
+	server.runs["running"] = &flowRun{ID: "running", Status: flowRunStatusRunning, StartedAt: time.Now().UTC().Add(-3 * time.Hour)}
+	server.prune(time.Now().UTC())
+	assert.Contains(t, server.runs, "running")
+}
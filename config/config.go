@@ -78,6 +78,10 @@ var (
 		PageSize:             newCfg("page_size", "20"),
 		SQLCLI:               newCfg("beta.sql_cli", "false"),
 		AuditLogs:            newCfg("beta.audit_logs", "false"),
+		ProxyHTTP:            newCfg("proxy.http_proxy", ""),
+		ProxyHTTPS:           newCfg("proxy.https_proxy", ""),
+		ProxyNoProxy:         newCfg("proxy.no_proxy", ""),
+		CertificatesCABundle: newCfg("certificates.ca_bundle", ""),
 	}
 
 	// viperHome is the viper object in the users home directory
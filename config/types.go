@@ -38,6 +38,10 @@ type cfgs struct {
 	PageSize             cfg
 	SQLCLI               cfg
 	AuditLogs            cfg
+	ProxyHTTP            cfg
+	ProxyHTTPS           cfg
+	ProxyNoProxy         cfg
+	CertificatesCABundle cfg
 }
 
 // Creates a new cfg struct
@@ -150,8 +150,7 @@ func getVersion() string {
 	}
 
 	// fallback case in which somehow we reach here without getting houston version
-	httpClient := NewHTTPClient()
-	client := NewClient(httpClient)
+	client := NewClient(requestHTTPClient)
 
 	version, versionErr = client.GetPlatformVersion(nil)
 	return version
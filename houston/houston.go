@@ -116,18 +116,32 @@ type Client struct {
 	HTTPClient *httputil.HTTPClient
 }
 
-func NewHTTPClient() *httputil.HTTPClient {
+// requestHTTPClient is the client of Request.Do, see SetHTTPClient
+var requestHTTPClient = httputil.NewHTTPClient()
+
+// SetHTTPClient sets the client used by Request.Do
+func SetHTTPClient(c *httputil.HTTPClient) {
+	requestHTTPClient = c
+}
+
+// NewHTTPClient returns the client to call Houston with, honoring the dial timeout, TLS verification,
+// proxy and certificates settings
+func NewHTTPClient(transportConfig *httputil.TransportConfig) *httputil.HTTPClient {
 	httpClient := httputil.NewHTTPClient()
 	// configure http transport
 	dialTimeout := config.CFG.HoustonDialTimeout.GetInt()
 	// #nosec
-	httpClient.HTTPClient.Transport = &http.Transport{
+	transport := &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout: time.Duration(dialTimeout) * time.Second,
 		}).Dial,
 		TLSHandshakeTimeout: time.Duration(dialTimeout) * time.Second,
 		TLSClientConfig:     &tls.Config{InsecureSkipVerify: config.CFG.HoustonSkipVerifyTLS.GetBool()},
 	}
+	if err := transportConfig.Apply(transport); err != nil {
+		newLogger.Warnf("Ignoring proxy and certificates settings: %s", err.Error())
+	}
+	httpClient.HTTPClient.Transport = transport
 	return httpClient
 }
 
@@ -161,7 +175,7 @@ func (r *Request) DoWithClient(api *Client) (*Response, error) {
 
 // Do (request) is a wrapper to more easily pass variables to a Client.Do request
 func (r *Request) Do() (*Response, error) {
-	return r.DoWithClient(newInternalClient(httputil.NewHTTPClient()))
+	return r.DoWithClient(newInternalClient(requestHTTPClient))
 }
 
 // Do fetches the current context, and returns Houston API response, error
@@ -54,6 +54,6 @@ func TestErrAuthTokenRefreshFailed(t *testing.T) {
 
 func TestNewHTTPClient(t *testing.T) {
 	testUtil.InitTestConfig(testUtil.SoftwarePlatform)
-	client := NewHTTPClient()
+	client := NewHTTPClient(&httputil.TransportConfig{})
 	assert.NotNil(t, client)
 }
@@ -0,0 +1,119 @@
+package httputil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var errNoCertificates = errors.New("no PEM certificates found")
+
+// TransportConfig holds the proxy and certificates settings of outbound calls
+// Proxies which are not set fall back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, in upper or lower case
+type TransportConfig struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+	// CABundle is the path of a PEM file with the certificates of a private CA, trusted on top of the system ones
+	CABundle string
+}
+
+// proxyConfig merges the configured proxies with the environment ones.
+// Unlike http.ProxyFromEnvironment the environment is read on every call, so it is not cached for the process lifetime.
+func (c *TransportConfig) proxyConfig() *httpproxy.Config {
+	proxy := httpproxy.FromEnvironment()
+	if c.HTTPProxy != "" {
+		proxy.HTTPProxy = c.HTTPProxy
+	}
+	if c.HTTPSProxy != "" {
+		proxy.HTTPSProxy = c.HTTPSProxy
+	}
+	if c.NoProxy != "" {
+		proxy.NoProxy = c.NoProxy
+	}
+	return proxy
+}
+
+// Proxy returns the proxy to use for a request, nil meaning no proxy
+func (c *TransportConfig) Proxy() func(*http.Request) (*url.URL, error) {
+	proxyFunc := c.proxyConfig().ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// ReadCABundle returns the content of the CA bundle, nil if none is configured
+func (c *TransportConfig) ReadCABundle() ([]byte, error) {
+	if c.CABundle == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(c.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CA bundle %s: %w", c.CABundle, err)
+	}
+	return pem, nil
+}
+
+// RootCAs returns the system certificates with the ones of the CA bundle, nil if no CA bundle is configured
+func (c *TransportConfig) RootCAs() (*x509.CertPool, error) {
+	pem, err := c.ReadCABundle()
+	if err != nil || pem == nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("error loading CA bundle %s: %w", c.CABundle, errNoCertificates)
+	}
+	return pool, nil
+}
+
+// Apply sets the proxy and the trusted certificates of transport
+func (c *TransportConfig) Apply(transport *http.Transport) error {
+	rootCAs, err := c.RootCAs()
+	if err != nil {
+		return err
+	}
+	transport.Proxy = c.Proxy()
+	if rootCAs != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	return nil
+}
+
+// Env returns the proxy environment variables to pass to processes, e.g. containers, in both cases as tools disagree on which one they read
+func (c *TransportConfig) Env() map[string]string {
+	proxy := c.proxyConfig()
+	env := map[string]string{}
+	for key, value := range map[string]string{"HTTP_PROXY": proxy.HTTPProxy, "HTTPS_PROXY": proxy.HTTPSProxy, "NO_PROXY": proxy.NoProxy} {
+		if value == "" {
+			continue
+		}
+		env[key] = value
+		env[strings.ToLower(key)] = value
+	}
+	return env
+}
+
+// NewHTTPClientWithTransportConfig returns a new HTTP Client honoring the proxy and certificates settings of c
+func NewHTTPClientWithTransportConfig(c *TransportConfig) (*HTTPClient, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := c.Apply(transport); err != nil {
+		return nil, err
+	}
+	return &HTTPClient{
+		HTTPClient: &http.Client{Transport: transport},
+	}, nil
+}
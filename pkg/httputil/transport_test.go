@@ -0,0 +1,89 @@
+package httputil
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCertificate is a self-signed certificate only used to check it gets loaded
+const testCertificate = `-----BEGIN CERTIFICATE-----
+MIIBhTCCASugAwIBAgIQIRi6zePL6mKjOipn+dNuaTAKBggqhkjOPQQDAjASMRAw
+DgYDVQQKEwdBY21lIENvMB4XDTE3MTAyMDE5NDMwNloXDTE4MTAyMDE5NDMwNlow
+EjEQMA4GA1UEChMHQWNtZSBDbzBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABD0d
+7VNhbWvZLWPuj/RtHFjvtJBEwOkhbN/BnnE8rnZR8+sbwnc/KhCk3FhnpHZnQz7B
+5aETbbIgmuvewdjvSBSjYzBhMA4GA1UdDwEB/wQEAwICpDATBgNVHSUEDDAKBggr
+BgEFBQcDATAPBgNVHRMBAf8EBTADAQH/MCkGA1UdEQQiMCCCDmxvY2FsaG9zdDo1
+NDUzgg4xMjcuMC4wLjE6NTQ1MzAKBggqhkjOPQQDAgNIADBFAiEA2zpJEPQyz6/l
+Wf86aX6PepsntZv2GYlA5UpabfT2EZICICpJ5h/iI+i341gBmLiAFQOyTDT+/wQc
+6MF9+Yw1Yy0t
+-----END CERTIFICATE-----`
+
+func TestTransportConfigProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("NO_PROXY", "")
+
+	t.Run("environment proxy is used when none is configured", func(t *testing.T) {
+		c := &TransportConfig{}
+		req, _ := http.NewRequest(http.MethodGet, "https://api.astronomer.io", http.NoBody)
+		proxy, err := c.Proxy()(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://env-proxy:3128", proxy.String())
+	})
+
+	t.Run("configured proxy takes precedence over the environment", func(t *testing.T) {
+		c := &TransportConfig{HTTPSProxy: "corporate-proxy:8080"}
+		req, _ := http.NewRequest(http.MethodGet, "https://api.astronomer.io", http.NoBody)
+		proxy, err := c.Proxy()(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://corporate-proxy:8080", proxy.String())
+	})
+
+	t.Run("no proxy hosts are reached directly", func(t *testing.T) {
+		c := &TransportConfig{HTTPSProxy: "corporate-proxy:8080", NoProxy: ".astronomer.io"}
+		req, _ := http.NewRequest(http.MethodGet, "https://api.astronomer.io", http.NoBody)
+		proxy, err := c.Proxy()(req)
+		assert.NoError(t, err)
+		assert.Nil(t, proxy)
+	})
+}
+
+func TestTransportConfigEnv(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+	c := &TransportConfig{HTTPSProxy: "http://corporate-proxy:8080", NoProxy: "localhost"}
+	assert.Equal(t, map[string]string{
+		"HTTPS_PROXY": "http://corporate-proxy:8080",
+		"https_proxy": "http://corporate-proxy:8080",
+		"NO_PROXY":    "localhost",
+		"no_proxy":    "localhost",
+	}, c.Env())
+}
+
+func TestNewHTTPClientWithTransportConfig(t *testing.T) {
+	t.Run("CA bundle is trusted", func(t *testing.T) {
+		caBundle := filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(caBundle, []byte(testCertificate), 0o600))
+		c, err := NewHTTPClientWithTransportConfig(&TransportConfig{CABundle: caBundle})
+		assert.NoError(t, err)
+		transport := c.HTTPClient.Transport.(*http.Transport)
+		assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+		assert.NotNil(t, transport.Proxy)
+	})
+
+	t.Run("missing CA bundle", func(t *testing.T) {
+		_, err := NewHTTPClientWithTransportConfig(&TransportConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("CA bundle without certificates", func(t *testing.T) {
+		caBundle := filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(caBundle, []byte("not a certificate"), 0o600))
+		_, err := NewHTTPClientWithTransportConfig(&TransportConfig{CABundle: caBundle})
+		assert.ErrorIs(t, err, errNoCertificates)
+	})
+}
@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ArtifactsManifestFileName = "manifest.json"
+	ArtifactsStatusFileName   = "status.json"
+	ArtifactsLogFileName      = "output.log"
+	ArtifactKindDAG           = "dag"
+	ArtifactKindStatus        = "status"
+	ArtifactKindLog           = "log"
+	artifactsDirMode          = 0o755
+	artifactsFileWriteMode    = 0o644
+)
+
+// Artifact describes a single file copied into the artifacts directory
+type Artifact struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Source string `json:"source,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ArtifactsManifest enumerates every artifact collected for a flow command
+type ArtifactsManifest struct {
+	Command   string     `json:"command"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// RunStatus is the outcome of a flow command executed in docker
+type RunStatus struct {
+	Command    []string  `json:"command"`
+	ExitCode   int64     `json:"exitCode"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// ArtifactsCollector copies the outputs of a flow command into a single host directory
+type ArtifactsCollector struct {
+	dir      string
+	manifest ArtifactsManifest
+}
+
+func NewArtifactsCollector(dir, command string) (*ArtifactsCollector, error) {
+	if err := os.MkdirAll(dir, artifactsDirMode); err != nil {
+		return nil, fmt.Errorf("error creating artifacts directory %s: %w", dir, err)
+	}
+	return &ArtifactsCollector{
+		dir: dir,
+		manifest: ArtifactsManifest{
+			Command:   command,
+			CreatedAt: time.Now().UTC(),
+			Artifacts: []Artifact{},
+		},
+	}, nil
+}
+
+// AddFile copies the file at src into the artifacts directory, grouped by kind
+func (c *ArtifactsCollector) AddFile(kind, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening artifact %s: %w", src, err)
+	}
+	defer in.Close()
+
+	relPath := filepath.Join(kind+"s", filepath.Base(src))
+	artifact, err := c.write(relPath, kind, in)
+	if err != nil {
+		return err
+	}
+	artifact.Source = src
+	c.manifest.Artifacts = append(c.manifest.Artifacts, artifact)
+	return nil
+}
+
+// AddContent writes data into the artifacts directory under the given name
+func (c *ArtifactsCollector) AddContent(kind, name string, data []byte) error {
+	artifact, err := c.write(name, kind, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	c.manifest.Artifacts = append(c.manifest.Artifacts, artifact)
+	return nil
+}
+
+// AddStatus serializes the run status as JSON into the artifacts directory
+func (c *ArtifactsCollector) AddStatus(status *RunStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing run status: %w", err)
+	}
+	return c.AddContent(ArtifactKindStatus, ArtifactsStatusFileName, data)
+}
+
+// WriteManifest writes the manifest enumerating all collected artifacts
+func (c *ArtifactsCollector) WriteManifest() error {
+	data, err := json.MarshalIndent(c.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing artifacts manifest: %w", err)
+	}
+	manifestPath := filepath.Join(c.dir, ArtifactsManifestFileName)
+	if err := os.WriteFile(manifestPath, data, artifactsFileWriteMode); err != nil {
+		return fmt.Errorf("error writing artifacts manifest %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+func (c *ArtifactsCollector) Manifest() ArtifactsManifest {
+	return c.manifest
+}
+
+func (c *ArtifactsCollector) write(relPath, kind string, r io.Reader) (Artifact, error) {
+	dst := filepath.Join(c.dir, relPath)
+	if err := os.MkdirAll(filepath.Dir(dst), artifactsDirMode); err != nil {
+		return Artifact{}, fmt.Errorf("error creating artifacts directory %s: %w", filepath.Dir(dst), err)
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, artifactsFileWriteMode)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("error creating artifact %s: %w", dst, err)
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), r)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("error writing artifact %s: %w", dst, err)
+	}
+	return Artifact{
+		Path:   filepath.ToSlash(relPath),
+		Kind:   kind,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
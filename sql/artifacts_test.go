@@ -0,0 +1,50 @@
+package sql
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactsCollector(t *testing.T) {
+	artifactsDir := filepath.Join(t.TempDir(), "artifacts")
+	dagFile := filepath.Join(t.TempDir(), "example.py")
+	err := os.WriteFile(dagFile, []byte("dag"), 0o600)
+	assert.NoError(t, err)
+
+	collector, err := NewArtifactsCollector(artifactsDir, "run")
+	assert.NoError(t, err)
+	assert.NoError(t, collector.AddContent(ArtifactKindLog, ArtifactsLogFileName, []byte("Sample log")))
+	assert.NoError(t, collector.AddStatus(&RunStatus{Command: []string{"run"}, Success: true}))
+	assert.NoError(t, collector.AddFile(ArtifactKindDAG, dagFile))
+	assert.NoError(t, collector.WriteManifest())
+
+	data, err := os.ReadFile(filepath.Join(artifactsDir, ArtifactsManifestFileName))
+	assert.NoError(t, err)
+	var manifest ArtifactsManifest
+	assert.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, collector.Manifest().Artifacts, manifest.Artifacts)
+	assert.Equal(t, "run", manifest.Command)
+	assert.Len(t, manifest.Artifacts, 3)
+
+	dag := manifest.Artifacts[2]
+	assert.Equal(t, "dags/example.py", dag.Path)
+	assert.Equal(t, dagFile, dag.Source)
+	assert.Equal(t, int64(3), dag.Size)
+	assert.Equal(t, "512d0f29088c76daad57b9c3569733021775483b2ca319fa56c99a07dd996d4e", dag.SHA256)
+
+	copied, err := os.ReadFile(filepath.Join(artifactsDir, "dags", "example.py"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dag", string(copied))
+}
+
+func TestArtifactsCollectorAddFileMissing(t *testing.T) {
+	collector, err := NewArtifactsCollector(t.TempDir(), "generate")
+	assert.NoError(t, err)
+	err = collector.AddFile(ArtifactKindDAG, filepath.Join(t.TempDir(), "missing.py"))
+	assert.ErrorContains(t, err, "error opening artifact")
+	assert.Empty(t, collector.Manifest().Artifacts)
+}
@@ -0,0 +1,145 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// PathSize returns the total size of the regular files at or under path
+func PathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error computing size of %s: %w", path, err)
+	}
+	return size, nil
+}
+
+// RemovePath deletes path and everything under it, returning the space reclaimed
+func RemovePath(path string) (int64, error) {
+	size, err := PathSize(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return 0, fmt.Errorf("error removing %s: %w", path, err)
+	}
+	return size, nil
+}
+
+// IsWithinDir reports whether path is dir or is located under it
+func IsWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// ImageExists reports whether the flow docker image is built
+func ImageExists() (bool, error) {
+	cli, err := Docker()
+	if err != nil {
+		return false, fmt.Errorf("docker client initialization failed %w", err)
+	}
+
+	if _, _, err := cli.ImageInspectWithRaw(context.Background(), SQLCliDockerImageName); err != nil {
+		if client.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("docker image inspect failed %w", err)
+	}
+	return true, nil
+}
+
+// isFlowImage reports whether an image was built from the flow Dockerfile, whose images are not labelled
+func isFlowImage(image *types.ImageInspect) bool {
+	if image.Config == nil || len(image.Config.Entrypoint) != 1 || image.Config.Entrypoint[0] != "flow" {
+		return false
+	}
+	for _, env := range image.Config.Env {
+		if env == "ASTRO_CLI=Yes" {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDanglingImages deletes the untagged images left by previous builds of the flow docker image,
+// returning the number of images removed and the space reclaimed
+func RemoveDanglingImages() (removed int, reclaimed int64, err error) {
+	ctx := context.Background()
+
+	cli, err := Docker()
+	if err != nil {
+		return 0, 0, fmt.Errorf("docker client initialization failed %w", err)
+	}
+
+	images, err := cli.ImageList(ctx, types.ImageListOptions{Filters: filters.NewArgs(filters.Arg("dangling", "true"))})
+	if err != nil {
+		return 0, 0, fmt.Errorf("docker image list failed %w", err)
+	}
+	for i := range images {
+		image, _, err := cli.ImageInspectWithRaw(ctx, images[i].ID)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				continue
+			}
+			return removed, reclaimed, fmt.Errorf("docker image inspect failed %w", err)
+		}
+		if !isFlowImage(&image) {
+			continue
+		}
+		if _, err := cli.ImageRemove(ctx, images[i].ID, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			return removed, reclaimed, fmt.Errorf("docker image remove failed %w", err)
+		}
+		removed++
+		reclaimed += image.Size
+	}
+	return removed, reclaimed, nil
+}
+
+// RemoveImage deletes the flow docker image, returning the space reclaimed.
+// It is not an error if the image does not exist.
+func RemoveImage() (int64, error) {
+	ctx := context.Background()
+
+	cli, err := Docker()
+	if err != nil {
+		return 0, fmt.Errorf("docker client initialization failed %w", err)
+	}
+
+	image, _, err := cli.ImageInspectWithRaw(ctx, SQLCliDockerImageName)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("docker image inspect failed %w", err)
+	}
+
+	if _, err := cli.ImageRemove(ctx, SQLCliDockerImageName, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+		return 0, fmt.Errorf("docker image remove failed %w", err)
+	}
+	return image.Size, nil
+}
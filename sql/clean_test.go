@@ -0,0 +1,121 @@
+package sql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astronomer/astro-cli/sql/mocks"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type imageNotFoundError struct{}
+
+func (imageNotFoundError) Error() string { return "No such image" }
+
+func (imageNotFoundError) NotFound() bool { return true }
+
+func TestRemovePath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.db"), []byte("12345"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "b.db"), []byte("123"), 0o600))
+
+	reclaimed, err := RemovePath(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), reclaimed)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRemovePathNotExist(t *testing.T) {
+	_, err := RemovePath(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "error computing size of")
+}
+
+func TestIsWithinDir(t *testing.T) {
+	assert.True(t, IsWithinDir("/project", "/project"))
+	assert.True(t, IsWithinDir("/project/dags/example.py", "/project"))
+	assert.True(t, IsWithinDir("/project/..data", "/project"))
+	assert.False(t, IsWithinDir("/other/dags", "/project"))
+	assert.False(t, IsWithinDir("/", "/project"))
+}
+
+func TestRemoveImage(t *testing.T) {
+	mockDocker := mocks.NewDockerBind(t)
+	Docker = func() (DockerBind, error) {
+		mockDocker.On("ImageInspectWithRaw", mock.Anything, SQLCliDockerImageName).Return(types.ImageInspect{Size: 1024}, nil, nil)
+		mockDocker.On("ImageRemove", mock.Anything, SQLCliDockerImageName, mock.Anything).Return(nil, nil)
+		return mockDocker, nil
+	}
+	defer func() { Docker = NewDockerBind }()
+
+	reclaimed, err := RemoveImage()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), reclaimed)
+}
+
+func TestRemoveImageNotFound(t *testing.T) {
+	mockDocker := mocks.NewDockerBind(t)
+	Docker = func() (DockerBind, error) {
+		mockDocker.On("ImageInspectWithRaw", mock.Anything, SQLCliDockerImageName).Return(types.ImageInspect{}, nil, imageNotFoundError{})
+		return mockDocker, nil
+	}
+	defer func() { Docker = NewDockerBind }()
+
+	reclaimed, err := RemoveImage()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), reclaimed)
+}
+
+func TestRemoveImageFailure(t *testing.T) {
+	mockDocker := mocks.NewDockerBind(t)
+	Docker = func() (DockerBind, error) {
+		mockDocker.On("ImageInspectWithRaw", mock.Anything, SQLCliDockerImageName).Return(types.ImageInspect{Size: 1024}, nil, nil)
+		mockDocker.On("ImageRemove", mock.Anything, SQLCliDockerImageName, mock.Anything).Return(nil, errMock)
+		return mockDocker, nil
+	}
+	defer func() { Docker = NewDockerBind }()
+
+	_, err := RemoveImage()
+	assert.ErrorIs(t, err, errMock)
+}
+
+func TestImageExists(t *testing.T) {
+	mockDocker := mocks.NewDockerBind(t)
+	mockDocker.On("ImageInspectWithRaw", mock.Anything, SQLCliDockerImageName).Return(types.ImageInspect{}, nil, nil).Once()
+	mockDocker.On("ImageInspectWithRaw", mock.Anything, SQLCliDockerImageName).Return(types.ImageInspect{}, nil, imageNotFoundError{}).Once()
+	Docker = func() (DockerBind, error) {
+		return mockDocker, nil
+	}
+	defer func() { Docker = NewDockerBind }()
+
+	exists, err := ImageExists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = ImageExists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRemoveDanglingImages(t *testing.T) {
+	flowConfig := &container.Config{Entrypoint: []string{"flow"}, Env: []string{"PATH=/usr/bin", "ASTRO_CLI=Yes"}}
+	mockDocker := mocks.NewDockerBind(t)
+	Docker = func() (DockerBind, error) {
+		mockDocker.On("ImageList", mock.Anything, mock.Anything).Return([]types.ImageSummary{{ID: "sha256:flow"}, {ID: "sha256:other"}}, nil)
+		mockDocker.On("ImageInspectWithRaw", mock.Anything, "sha256:flow").Return(types.ImageInspect{Size: 1024, Config: flowConfig}, nil, nil)
+		mockDocker.On("ImageInspectWithRaw", mock.Anything, "sha256:other").Return(types.ImageInspect{Size: 2048, Config: &container.Config{}}, nil, nil)
+		mockDocker.On("ImageRemove", mock.Anything, "sha256:flow", mock.Anything).Return(nil, nil)
+		return mockDocker, nil
+	}
+	defer func() { Docker = NewDockerBind }()
+
+	removed, reclaimed, err := RemoveDanglingImages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, int64(1024), reclaimed)
+}
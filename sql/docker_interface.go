@@ -22,6 +22,9 @@ type DockerBind interface {
 	ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error)
 	ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 	ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error
+	ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error)
+	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
 }
 
 func (d DockerBinder) ImageBuild(ctx context.Context, buildContext io.Reader, options *types.ImageBuildOptions) (types.ImageBuildResponse, error) {
@@ -48,6 +51,18 @@ func (d DockerBinder) ContainerRemove(ctx context.Context, containerID string, o
 	return d.cli.ContainerRemove(ctx, containerID, options)
 }
 
+func (d DockerBinder) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	return d.cli.ImageInspectWithRaw(ctx, imageID)
+}
+
+func (d DockerBinder) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	return d.cli.ImageRemove(ctx, imageID, options)
+}
+
+func (d DockerBinder) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return d.cli.ImageList(ctx, options)
+}
+
 func NewDockerBind() (DockerBind, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
@@ -8,6 +8,13 @@ import (
 var (
 	errArgNotSetError             = errors.New("argument not set")
 	errDockerNonZeroExitCodeError = errors.New("docker command has returned a non-zero exit code")
+	errTemplateNotFound           = errors.New("template not found")
+	errTemplateChecksumMismatch   = errors.New("template checksum mismatch")
+	errTemplateInvalidPath        = errors.New("template bundle contains an invalid path")
+	errTarGzTooLarge              = errors.New("archive is too large once extracted")
+	errTemplatesIndexSignature    = errors.New("templates index signature verification failed")
+	// errTemplatesIndexPublicKeyNotSet is returned by builds without a pinned key, e.g. development builds
+	errTemplatesIndexPublicKeyNotSet = errors.New("no public key to verify the templates index with")
 )
 
 func ArgNotSetError(argument string) error {
@@ -17,3 +24,11 @@ func ArgNotSetError(argument string) error {
 func DockerNonZeroExitCodeError(statusCode int64) error {
 	return fmt.Errorf("%w:%d", errDockerNonZeroExitCodeError, statusCode)
 }
+
+func TemplateNotFoundError(name string) error {
+	return fmt.Errorf("%w:%s", errTemplateNotFound, name)
+}
+
+func TemplateChecksumMismatchError(name, expected, actual string) error {
+	return fmt.Errorf("%w:%s expected %s got %s", errTemplateChecksumMismatch, name, expected, actual)
+}
@@ -17,3 +17,15 @@ func TestDockerNonZeroExitCodeError(t *testing.T) {
 	expectedErrorMessage := "docker command has returned a non-zero exit code:1"
 	assert.EqualError(t, errorMessage, expectedErrorMessage)
 }
+
+func TestTemplateNotFoundError(t *testing.T) {
+	errorMessage := TemplateNotFoundError("sample_template")
+	expectedErrorMessage := "template not found:sample_template"
+	assert.EqualError(t, errorMessage, expectedErrorMessage)
+}
+
+func TestTemplateChecksumMismatchError(t *testing.T) {
+	errorMessage := TemplateChecksumMismatchError("sample_template", "abc", "def")
+	expectedErrorMessage := "template checksum mismatch:sample_template expected abc got def"
+	assert.EqualError(t, errorMessage, expectedErrorMessage)
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/user"
 	"strings"
+	"sync"
 
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/sql/include"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -23,9 +26,16 @@ const (
 	SQLCLIDockerfileWriteMode = 0o600
 	SQLCliDockerImageName     = "sql_cli"
 	PythonVersion             = "3.9"
+	caBundleBuildArg          = "ASTRO_CA_BUNDLE"
 )
 
+// imageBuildLock serializes image builds, which share the dockerfile written to the working directory
+var imageBuildLock sync.Mutex
+
 var (
+	// Transport holds the proxy and certificates settings used by the flow HTTP calls, image build and containers
+	Transport = &httputil.TransportConfig{}
+
 	Docker          = NewDockerBind
 	Io              = NewIoBind
 	DisplayMessages = OriginalDisplayMessages
@@ -83,7 +93,84 @@ var ConvertReadCloserToString = func(readCloser io.ReadCloser) (string, error) {
 	return buf.String(), nil
 }
 
+func buildImage(ctx context.Context, cli DockerBind, baseImage, astroSQLCliVersion string, currentUser *user.User, profile *Profile) error {
+	imageBuildLock.Lock()
+	defer imageBuildLock.Unlock()
+	// the wait for a concurrent build is not part of the build
+	defer profile.TrackPhase(PhaseImageBuild)()
+
+	dockerfileContent := []byte(fmt.Sprintf(include.Dockerfile, baseImage, astroSQLCliVersion, currentUser.Username, currentUser.Uid, currentUser.Username))
+	if err := Os().WriteFile(SQLCliDockerfilePath, dockerfileContent, SQLCLIDockerfileWriteMode); err != nil {
+		return fmt.Errorf("error writing dockerfile %w", err)
+	}
+	defer os.Remove(SQLCliDockerfilePath)
+
+	buildArgs, err := getBuildArgs()
+	if err != nil {
+		return err
+	}
+
+	body, err := cli.ImageBuild(
+		ctx,
+		getContext(SQLCliDockerfilePath),
+		&types.ImageBuildOptions{
+			Dockerfile: SQLCliDockerfilePath,
+			Tags:       []string{SQLCliDockerImageName},
+			BuildArgs:  buildArgs,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("image building failed %w", err)
+	}
+
+	if err := DisplayMessages(body.Body); err != nil {
+		return fmt.Errorf("image build response read failed %w", err)
+	}
+	return nil
+}
+
+// getBuildArgs passes the proxies, which are predefined build args, and the CA bundle to the image build
+func getBuildArgs() (map[string]*string, error) {
+	buildArgs := map[string]*string{}
+	for key, value := range Transport.Env() {
+		value := value
+		buildArgs[key] = &value
+	}
+	caBundle, err := Transport.ReadCABundle()
+	if err != nil {
+		return nil, err
+	}
+	if caBundle != nil {
+		value := string(caBundle)
+		buildArgs[caBundleBuildArg] = &value
+	}
+	return buildArgs, nil
+}
+
+// getContainerEnv passes the proxies to the container, the CA bundle being installed in the image
+func getContainerEnv() []string {
+	env := []string{}
+	for key, value := range Transport.Env() {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	return env
+}
+
+// newHTTPClient returns an HTTP client honoring the proxy and certificates settings of Transport
+func newHTTPClient() (*http.Client, error) {
+	httpClient, err := httputil.NewHTTPClientWithTransportConfig(Transport)
+	if err != nil {
+		return nil, err
+	}
+	return httpClient.HTTPClient, nil
+}
+
 var ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (exitCode int64, output io.ReadCloser, err error) {
+	return ExecuteCmdInDockerWithProfile(nil, cmd, args, flags, mountDirs, returnOutput)
+}
+
+// ExecuteCmdInDockerWithProfile runs the command like ExecuteCmdInDocker, recording the time spent in each phase into profile
+var ExecuteCmdInDockerWithProfile = func(profile *Profile, cmd, args []string, flags map[string]string, mountDirs []string, returnOutput bool) (exitCode int64, output io.ReadCloser, err error) {
 	var statusCode int64
 	var cout io.ReadCloser
 
@@ -94,38 +181,23 @@ var ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mount
 		return statusCode, cout, fmt.Errorf("docker client initialization failed %w", err)
 	}
 
+	stopVersionResolution := profile.TrackPhase(PhaseVersionResolution)
 	astroSQLCliVersion, err := getPypiVersion(astroSQLCLIProjectURL)
 	if err != nil {
 		return statusCode, cout, err
 	}
+	profile.SetSQLCLIVersion(astroSQLCliVersion)
 
 	baseImage, err := getBaseDockerImageURI(astroSQLCLIConfigURL)
 	if err != nil {
 		fmt.Println(err)
 	}
+	stopVersionResolution()
 
 	currentUser, _ := user.Current()
 
-	dockerfileContent := []byte(fmt.Sprintf(include.Dockerfile, baseImage, astroSQLCliVersion, currentUser.Username, currentUser.Uid, currentUser.Username))
-	if err := Os().WriteFile(SQLCliDockerfilePath, dockerfileContent, SQLCLIDockerfileWriteMode); err != nil {
-		return statusCode, cout, fmt.Errorf("error writing dockerfile %w", err)
-	}
-	defer os.Remove(SQLCliDockerfilePath)
-
-	body, err := cli.ImageBuild(
-		ctx,
-		getContext(SQLCliDockerfilePath),
-		&types.ImageBuildOptions{
-			Dockerfile: SQLCliDockerfilePath,
-			Tags:       []string{SQLCliDockerImageName},
-		},
-	)
-	if err != nil {
-		return statusCode, cout, fmt.Errorf("image building failed %w", err)
-	}
-
-	if err := DisplayMessages(body.Body); err != nil {
-		return statusCode, cout, fmt.Errorf("image build response read failed %w", err)
+	if err := buildImage(ctx, cli, baseImage, astroSQLCliVersion, currentUser, profile); err != nil {
+		return statusCode, cout, err
 	}
 
 	cmd = append(cmd, args...)
@@ -138,11 +210,13 @@ var ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mount
 		binds = append(binds, fmt.Sprintf("%s:%s", mountDir, mountDir))
 	}
 
+	stopContainerStart := profile.TrackPhase(PhaseContainerStart)
 	resp, err := cli.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: SQLCliDockerImageName,
 			Cmd:   cmd,
+			Env:   getContainerEnv(),
 			Tty:   true,
 			User:  fmt.Sprintf("%s:%s", currentUser.Uid, currentUser.Gid),
 		},
@@ -160,6 +234,9 @@ var ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mount
 	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
 		return statusCode, cout, fmt.Errorf("docker container start failed %w", err)
 	}
+	stopContainerStart()
+
+	stopCLIExecution := profile.TrackPhase(PhaseCLIExecution)
 
 	statusCh, errCh := cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
 	select {
@@ -170,6 +247,7 @@ var ExecuteCmdInDocker = func(cmd, args []string, flags map[string]string, mount
 	case status := <-statusCh:
 		statusCode = status.StatusCode
 	}
+	stopCLIExecution()
 
 	cout, err = cli.ContainerLogs(ctx, resp.ID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
 	if err != nil {
@@ -28,7 +28,10 @@ var (
 )
 
 func GetPypiVersion(projectURL string) (string, error) {
-	httpClient := &http.Client{}
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return "", err
+	}
 	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, projectURL, http.NoBody)
 	if err != nil {
 		return "", fmt.Errorf("error creating HTTP request %w", err)
@@ -60,7 +63,10 @@ func GetPypiVersion(projectURL string) (string, error) {
 }
 
 func GetBaseDockerImageURI(configURL string) (string, error) {
-	httpClient := &http.Client{}
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return defaultDockerImageURI, fmt.Errorf("%w. Using the default", err)
+	}
 	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, configURL, http.NoBody)
 	if err != nil {
 		return defaultDockerImageURI, fmt.Errorf("error creating HTTP request %w. Using the default", err)
@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/astronomer/astro-cli/pkg/httputil"
 	"github.com/astronomer/astro-cli/sql/mocks"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -106,6 +109,58 @@ func TestExecuteCmdInDockerSuccess(t *testing.T) {
 	Os = NewOsBind
 }
 
+func TestExecuteCmdInDockerWithProfilePhases(t *testing.T) {
+	const lockWait = 100 * time.Millisecond
+	mockDocker := mocks.NewDockerBind(t)
+	mockDocker.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Return(imageBuildResponse, nil)
+	mockDocker.On("ContainerCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(containerCreateCreatedBody, nil)
+	mockDocker.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDocker.On("ContainerWait", mock.Anything, mock.Anything, mock.Anything).Return(getContainerWaitResponse(false))
+	mockDocker.On("ContainerLogs", mock.Anything, mock.Anything, mock.Anything).Return(sampleLog, nil)
+	mockDocker.On("ContainerRemove", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	Docker = func() (DockerBind, error) {
+		return mockDocker, nil
+	}
+	mockOs := mocks.NewOsBind(t)
+	mockOs.On("WriteFile", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	Os = func() OsBind {
+		return mockOs
+	}
+	getPypiVersion = func(projectURL string) (string, error) {
+		return "1.3.0", nil
+	}
+	getBaseDockerImageURI = func(astroSQLCLIConfigURL string) (string, error) {
+		return "quay.io/astronomer/astro-runtime:7.3.0-base", nil
+	}
+	DisplayMessages = mockDisplayMessagesNil
+	defer func() {
+		Docker = NewDockerBind
+		Os = NewOsBind
+		getPypiVersion = GetPypiVersion
+		getBaseDockerImageURI = GetBaseDockerImageURI
+		DisplayMessages = OriginalDisplayMessages
+	}()
+
+	// hold the image build lock as a concurrent build would
+	imageBuildLock.Lock()
+	go func() {
+		time.Sleep(lockWait)
+		imageBuildLock.Unlock()
+	}()
+
+	profile := StartProfile(testCommand)
+	_, _, err := ExecuteCmdInDockerWithProfile(profile, testCommand, nil, nil, nil, true)
+	assert.NoError(t, err)
+
+	phases := []string{}
+	for _, phase := range profile.Phases {
+		phases = append(phases, phase.Name)
+	}
+	assert.Equal(t, []string{PhaseVersionResolution, PhaseImageBuild, PhaseContainerStart, PhaseCLIExecution}, phases)
+	assert.Less(t, profile.Phases[1].Seconds, lockWait.Seconds())
+	assert.Equal(t, "1.3.0", profile.SQLCLIVersion)
+}
+
 func TestDisplayMessages(t *testing.T) {
 	orgStdout := os.Stdout
 	defer func() { os.Stdout = orgStdout }()
@@ -324,3 +379,26 @@ func TestConvertReadCloserToStringFailure(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 	Io = NewIoBind
 }
+
+func TestGetBuildArgsAndContainerEnv(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+	originalTransport := Transport
+	defer func() { Transport = originalTransport }()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caBundle, []byte("test-ca"), 0o600))
+	Transport = &httputil.TransportConfig{HTTPSProxy: "http://corporate-proxy:8080", CABundle: caBundle}
+
+	buildArgs, err := getBuildArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://corporate-proxy:8080", *buildArgs["HTTPS_PROXY"])
+	assert.Equal(t, "http://corporate-proxy:8080", *buildArgs["https_proxy"])
+	assert.Equal(t, "test-ca", *buildArgs[caBundleBuildArg])
+	assert.ElementsMatch(t, []string{"HTTPS_PROXY=http://corporate-proxy:8080", "https_proxy=http://corporate-proxy:8080"}, getContainerEnv())
+
+	Transport = &httputil.TransportConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}
+	_, err = getBuildArgs()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
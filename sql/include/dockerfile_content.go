@@ -10,6 +10,13 @@ FROM %s
 ENV ASTRO_CLI Yes
 ENV AIRFLOW__ASTRONOMER__UPDATE_CHECK_INTERVAL 0
 
+# Trust the private CA set in certificates.ca_bundle, e.g. for proxies intercepting TLS
+ARG ASTRO_CA_BUNDLE
+RUN if [ -n "$ASTRO_CA_BUNDLE" ]; then \
+        echo "$ASTRO_CA_BUNDLE" > /usr/local/share/ca-certificates/astro-ca-bundle.crt && update-ca-certificates; \
+    fi
+ENV REQUESTS_CA_BUNDLE=${ASTRO_CA_BUNDLE:+/etc/ssl/certs/ca-certificates.crt}
+
 # build-essential is necessary to be able to build wheels for snowflake-connector-python
 RUN apt-install-and-clean \
         build-essential
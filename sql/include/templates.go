@@ -0,0 +1,12 @@
+package include
+
+import "embed"
+
+// Templates holds the flow project templates shipped with the CLI,
+// used when the versioned template bundle cannot be fetched
+
+//go:embed templates
+var Templates embed.FS
+
+// TemplatesDir is the root directory of the embedded templates
+const TemplatesDir = "templates"
@@ -0,0 +1,8 @@
+---
+conn_id: sqlite_conn
+---
+SELECT Title, Rating
+FROM imdb_movies
+WHERE Genre1 == 'Animation'
+ORDER BY Rating DESC
+LIMIT 5;
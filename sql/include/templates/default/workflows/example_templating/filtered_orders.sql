@@ -0,0 +1,4 @@
+---
+conn_id: sqlite_conn
+---
+SELECT * FROM orders WHERE amount > 10;
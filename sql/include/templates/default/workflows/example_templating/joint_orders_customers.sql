@@ -0,0 +1,7 @@
+---
+conn_id: sqlite_conn
+---
+SELECT c.customer_id, c.customer_name, c.customer_email, o.order_id, o.amount
+FROM {{filtered_orders}} o
+JOIN customers c
+ON c.customer_id = o.customer_id;
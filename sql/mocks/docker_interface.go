@@ -141,6 +141,82 @@ func (_m *DockerBind) ImageBuild(ctx context.Context, buildContext io.Reader, op
 	return r0, r1
 }
 
+// ImageInspectWithRaw provides a mock function with given fields: ctx, imageID
+func (_m *DockerBind) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	ret := _m.Called(ctx, imageID)
+
+	var r0 types.ImageInspect
+	if rf, ok := ret.Get(0).(func(context.Context, string) types.ImageInspect); ok {
+		r0 = rf(ctx, imageID)
+	} else {
+		r0 = ret.Get(0).(types.ImageInspect)
+	}
+
+	var r1 []byte
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, imageID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, imageID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ImageList provides a mock function with given fields: ctx, options
+func (_m *DockerBind) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	ret := _m.Called(ctx, options)
+
+	var r0 []types.ImageSummary
+	if rf, ok := ret.Get(0).(func(context.Context, types.ImageListOptions) []types.ImageSummary); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ImageSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.ImageListOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImageRemove provides a mock function with given fields: ctx, imageID, options
+func (_m *DockerBind) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	ret := _m.Called(ctx, imageID, options)
+
+	var r0 []types.ImageDeleteResponseItem
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.ImageRemoveOptions) []types.ImageDeleteResponseItem); ok {
+		r0 = rf(ctx, imageID, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ImageDeleteResponseItem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.ImageRemoveOptions) error); ok {
+		r1 = rf(ctx, imageID, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewDockerBind interface {
 	mock.TestingT
 	Cleanup(func())
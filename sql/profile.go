@@ -0,0 +1,133 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	PhaseVersionResolution = "version_resolution"
+	PhaseImageBuild        = "image_build"
+	PhaseContainerStart    = "container_start"
+	PhaseCLIExecution      = "cli_execution"
+	profileFileMode        = 0o644
+	airflowLogTimeFmt      = "20060102T150405"
+)
+
+// taskLogRegex matches the line Airflow logs when a task finishes, e.g.
+// Marking task as SUCCESS. dag_id=example, task_id=top_animations, execution_date=20230101T000000, start_date=20230405T101112, end_date=20230405T101114
+var taskLogRegex = regexp.MustCompile(`Marking task as (\w+)\. dag_id=([^,]+), task_id=([^,]+),.* start_date=(\d{8}T\d{6}), end_date=(\d{8}T\d{6})`)
+
+// PhaseTiming is the wall-clock time spent in a phase of a flow command
+type PhaseTiming struct {
+	Name      string    `json:"name"`
+	StartedAt time.Time `json:"startedAt"`
+	Seconds   float64   `json:"seconds"`
+}
+
+// TaskTiming is the duration of a task, as reported by Airflow in the command logs
+type TaskTiming struct {
+	DagID   string  `json:"dagId"`
+	TaskID  string  `json:"taskId"`
+	State   string  `json:"state"`
+	Seconds float64 `json:"seconds"`
+}
+
+// Profile records where the time of a flow command goes, so runs can be compared across projects and SQL CLI versions
+type Profile struct {
+	Command       []string      `json:"command"`
+	SQLCLIVersion string        `json:"sqlCliVersion,omitempty"`
+	Success       bool          `json:"success"`
+	StartedAt     time.Time     `json:"startedAt"`
+	FinishedAt    time.Time     `json:"finishedAt"`
+	TotalSeconds  float64       `json:"totalSeconds"`
+	Phases        []PhaseTiming `json:"phases"`
+	Tasks         []TaskTiming  `json:"tasks"`
+
+	mu sync.Mutex
+}
+
+// StartProfile starts profiling command. The profile is passed to ExecuteCmdInDockerWithProfile to record its phases.
+func StartProfile(command []string) *Profile {
+	return &Profile{Command: command, StartedAt: time.Now().UTC(), Phases: []PhaseTiming{}, Tasks: []TaskTiming{}}
+}
+
+// Stop sets the outcome and the total duration of the profile
+func (p *Profile) Stop(success bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Success = success
+	p.FinishedAt = time.Now().UTC()
+	p.TotalSeconds = p.FinishedAt.Sub(p.StartedAt).Seconds()
+}
+
+// TrackPhase records the time until the returned function is called as phase name.
+// It is a no-op on a nil profile, so commands which are not profiled do not need to check.
+func (p *Profile) TrackPhase(name string) func() {
+	if p == nil {
+		return func() {}
+	}
+	startedAt := time.Now().UTC()
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.Phases = append(p.Phases, PhaseTiming{Name: name, StartedAt: startedAt, Seconds: time.Since(startedAt).Seconds()})
+	}
+}
+
+// SetSQLCLIVersion records the version of the SQL CLI the command ran with, it is a no-op on a nil profile
+func (p *Profile) SetSQLCLIVersion(version string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.SQLCLIVersion = version
+}
+
+// RecordTaskDurations adds the tasks found in the command logs, it is a no-op on a nil profile
+func (p *Profile) RecordTaskDurations(logs string) {
+	if p == nil {
+		return
+	}
+	tasks := ParseTaskDurations(logs)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Tasks = append(p.Tasks, tasks...)
+}
+
+// ParseTaskDurations returns the duration of the tasks Airflow marked as finished in logs.
+// Airflow logs these dates with a second precision.
+func ParseTaskDurations(logs string) []TaskTiming {
+	tasks := []TaskTiming{}
+	for _, match := range taskLogRegex.FindAllStringSubmatch(logs, -1) {
+		startDate, err := time.Parse(airflowLogTimeFmt, match[4])
+		if err != nil {
+			continue
+		}
+		endDate, err := time.Parse(airflowLogTimeFmt, match[5])
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, TaskTiming{DagID: match[2], TaskID: match[3], State: match[1], Seconds: endDate.Sub(startDate).Seconds()})
+	}
+	return tasks
+}
+
+// Write writes the profile as JSON to path
+func (p *Profile) Write(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, profileFileMode); err != nil {
+		return fmt.Errorf("error writing profile %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTaskDurations(t *testing.T) {
+	logs := `[2023-04-05, 10:11:15 UTC] {taskinstance.py:1327} INFO - Marking task as SUCCESS. dag_id=example, task_id=extract, execution_date=20230101T000000, start_date=20230405T101112, end_date=20230405T101115
+[2023-04-05, 10:11:20 UTC] {taskinstance.py:1327} INFO - Marking task as FAILED. dag_id=example, task_id=load, map_index=0, execution_date=20230101T000000, start_date=20230405T101115, end_date=20230405T101120
+Completed running the workflow example.`
+	assert.Equal(t, []TaskTiming{
+		{DagID: "example", TaskID: "extract", State: "SUCCESS", Seconds: 3},
+		{DagID: "example", TaskID: "load", State: "FAILED", Seconds: 5},
+	}, ParseTaskDurations(logs))
+	assert.Empty(t, ParseTaskDurations("Sample log"))
+}
+
+func TestProfile(t *testing.T) {
+	t.Run("phases are recorded into the profile", func(t *testing.T) {
+		profile := StartProfile([]string{"run", "example"})
+		profile.SetSQLCLIVersion("1.3.0")
+		profile.TrackPhase(PhaseImageBuild)()
+		stopCLIExecution := profile.TrackPhase(PhaseCLIExecution)
+		stopCLIExecution()
+		profile.RecordTaskDurations("Marking task as SUCCESS. dag_id=example, task_id=extract, execution_date=20230101T000000, start_date=20230405T101112, end_date=20230405T101113")
+		profile.Stop(true)
+
+		assert.True(t, profile.Success)
+		assert.Equal(t, "1.3.0", profile.SQLCLIVersion)
+		assert.Len(t, profile.Phases, 2)
+		assert.Equal(t, PhaseImageBuild, profile.Phases[0].Name)
+		assert.Equal(t, PhaseCLIExecution, profile.Phases[1].Name)
+		assert.Len(t, profile.Tasks, 1)
+		assert.False(t, profile.FinishedAt.Before(profile.StartedAt))
+	})
+
+	t.Run("nothing is recorded without a profile", func(t *testing.T) {
+		var profile *Profile
+		assert.NotPanics(t, func() {
+			profile.SetSQLCLIVersion("1.3.0")
+			profile.TrackPhase(PhaseImageBuild)()
+			profile.RecordTaskDurations("Sample log")
+		})
+	})
+
+	t.Run("profile is written as JSON", func(t *testing.T) {
+		profile := StartProfile([]string{"run", "example"})
+		profile.Stop(false)
+		path := filepath.Join(t.TempDir(), "profile.json")
+		assert.NoError(t, profile.Write(path))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		var written Profile
+		assert.NoError(t, json.Unmarshal(data, &written))
+		assert.Equal(t, []string{"run", "example"}, written.Command)
+		assert.False(t, written.Success)
+	})
+}
@@ -0,0 +1,398 @@
+package sql
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astronomer/astro-cli/config"
+	"github.com/astronomer/astro-cli/sql/include"
+)
+
+const (
+	AstroSQLCLITemplatesIndexURL = "https://raw.githubusercontent.com/astronomer/astro-sdk/astro-cli/sql-cli/templates/index.json"
+	DefaultTemplateName          = "default"
+	TemplateSourceCache          = "cache"
+	TemplateSourceEmbedded       = "embedded"
+	embeddedTemplateVersion      = "embedded"
+	templatesIndexFileName       = "index.json"
+	templatesIndexSignatureExt   = ".sig"
+	maxTemplateExtractedSize     = 100 << 20
+	templatesCacheDirMode        = 0o755
+	templateFileWriteMode        = 0o644
+)
+
+var (
+	// TemplatesCacheDir is where verified template bundles are extracted, one directory per name and version
+	TemplatesCacheDir = filepath.Join(config.HomeConfigPath, "cache", "flow", "templates")
+	// TemplatesIndexPublicKey is the base64 ed25519 public key the templates index is signed with.
+	// It is pinned at build time with -ldflags "-X github.com/astronomer/astro-cli/sql.TemplatesIndexPublicKey=<key>",
+	// when it is not set the templates are never updated and the embedded ones are used.
+	TemplatesIndexPublicKey = ""
+	updateTemplates         = UpdateTemplates
+)
+
+// TemplateBundle is an entry of the templates index, pointing to a versioned tar.gz bundle
+type TemplateBundle struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	SHA256      string `json:"sha256"`
+}
+
+type TemplatesIndex struct {
+	Templates []TemplateBundle `json:"templates"`
+}
+
+// Template is a resolved template, either from the local cache or from the copy embedded in the CLI
+type Template struct {
+	Name    string
+	Version string
+	Source  string
+	Files   fs.FS
+}
+
+func httpGet(url string) ([]byte, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request %w", err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s,  %w", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading %s, status %d", url, res.StatusCode) //nolint:goerr113
+	}
+	return io.ReadAll(res.Body)
+}
+
+// verifyTemplatesIndex checks the base64 ed25519 signature of the index against TemplatesIndexPublicKey
+func verifyTemplatesIndex(data, signature []byte) error {
+	if TemplatesIndexPublicKey == "" {
+		return errTemplatesIndexPublicKeyNotSet
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(TemplatesIndexPublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", errTemplatesIndexSignature)
+	}
+	decodedSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errTemplatesIndexSignature, err.Error())
+	}
+	if !ed25519.Verify(publicKey, data, decodedSignature) {
+		return errTemplatesIndexSignature
+	}
+	return nil
+}
+
+// FetchTemplatesIndex downloads the index and its signature, published next to it with a .sig extension.
+// The checksums of the bundles are only trusted once the signature of the index is verified.
+func FetchTemplatesIndex(indexURL string) (*TemplatesIndex, error) {
+	if TemplatesIndexPublicKey == "" {
+		return nil, errTemplatesIndexPublicKeyNotSet
+	}
+	data, err := httpGet(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := httpGet(indexURL + templatesIndexSignatureExt)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyTemplatesIndex(data, signature); err != nil {
+		return nil, err
+	}
+	var index TemplatesIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("error parsing templates index %w", err)
+	}
+	return &index, nil
+}
+
+// UpdateTemplates downloads every bundle listed in the index, verifies its SHA256 checksum
+// and extracts it into the cache. Bundles already cached for the same version are not downloaded again.
+func UpdateTemplates(indexURL string) ([]TemplateBundle, error) {
+	index, err := FetchTemplatesIndex(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(TemplatesCacheDir, templatesCacheDirMode); err != nil {
+		return nil, fmt.Errorf("error creating templates cache directory %w", err)
+	}
+	for i := range index.Templates {
+		if err := fetchTemplateBundle(&index.Templates[i]); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing templates index %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(TemplatesCacheDir, templatesIndexFileName), data, templateFileWriteMode); err != nil {
+		return nil, fmt.Errorf("error writing templates index %w", err)
+	}
+	return index.Templates, nil
+}
+
+func templateCacheDir(name, version string) string {
+	return filepath.Join(TemplatesCacheDir, name, version)
+}
+
+func fetchTemplateBundle(bundle *TemplateBundle) error {
+	dst := templateCacheDir(bundle.Name, bundle.Version)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+
+	data, err := httpGet(bundle.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if checksum := hex.EncodeToString(sum[:]); !strings.EqualFold(checksum, bundle.SHA256) {
+		return TemplateChecksumMismatchError(bundle.Name, bundle.SHA256, checksum)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), templatesCacheDirMode); err != nil {
+		return fmt.Errorf("error creating templates cache directory %w", err)
+	}
+	// extract next to the final location so a failed extraction never leaves a partial template behind
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dst), ".download-")
+	if err != nil {
+		return fmt.Errorf("error creating templates cache directory %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := ExtractTarGz(bytes.NewReader(data), tmpDir, maxTemplateExtractedSize); err != nil {
+		return fmt.Errorf("error extracting template %s: %w", bundle.Name, err)
+	}
+	return os.Rename(tmpDir, dst)
+}
+
+// ExtractTarGz extracts a gzipped tarball into dst, rejecting entries which would escape it
+// and stopping once more than maxSize bytes have been extracted, e.g. for gzip bombs
+func ExtractTarGz(r io.Reader, dst string, maxSize int64) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	var extracted int64
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, filepath.FromSlash(header.Name)) //nolint:gosec
+		if !strings.HasPrefix(target, filepath.Clean(dst)+string(os.PathSeparator)) {
+			return fmt.Errorf("%w:%s", errTemplateInvalidPath, header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, templatesCacheDirMode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), templatesCacheDirMode); err != nil {
+				return err
+			}
+			// read one byte more than allowed to detect entries going over the limit, whatever their header says
+			written, err := writeFileLimit(target, tr, maxSize-extracted+1)
+			if err != nil {
+				return err
+			}
+			extracted += written
+			if extracted > maxSize {
+				return fmt.Errorf("%w: more than %d bytes", errTarGzTooLarge, maxSize)
+			}
+		}
+	}
+}
+
+func writeFileLimit(path string, r io.Reader, limit int64) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, templateFileWriteMode)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(f, io.LimitReader(r, limit))
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, templateFileWriteMode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r) //nolint:gosec
+	return err
+}
+
+func readCachedTemplatesIndex() (*TemplatesIndex, error) {
+	data, err := os.ReadFile(filepath.Join(TemplatesCacheDir, templatesIndexFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &TemplatesIndex{}, nil
+		}
+		return nil, fmt.Errorf("error reading templates index %w", err)
+	}
+	var index TemplatesIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("error parsing templates index %w", err)
+	}
+	return &index, nil
+}
+
+func embeddedTemplateNames() ([]string, error) {
+	entries, err := fs.ReadDir(include.Templates, include.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func embeddedTemplate(name string) (*Template, error) {
+	files, err := fs.Sub(include.Templates, filepath.ToSlash(filepath.Join(include.TemplatesDir, name)))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(files, "."); err != nil {
+		return nil, TemplateNotFoundError(name)
+	}
+	return &Template{Name: name, Version: embeddedTemplateVersion, Source: TemplateSourceEmbedded, Files: files}, nil
+}
+
+// ListTemplates returns the cached templates and the embedded ones not shadowed by the cache, sorted by name
+func ListTemplates() ([]Template, error) {
+	index, err := readCachedTemplatesIndex()
+	if err != nil {
+		return nil, err
+	}
+	templates := []Template{}
+	cached := map[string]bool{}
+	for _, bundle := range index.Templates {
+		dir := templateCacheDir(bundle.Name, bundle.Version)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		cached[bundle.Name] = true
+		templates = append(templates, Template{Name: bundle.Name, Version: bundle.Version, Source: TemplateSourceCache, Files: os.DirFS(dir)})
+	}
+
+	names, err := embeddedTemplateNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if cached[name] {
+			continue
+		}
+		template, err := embeddedTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	sort.SliceStable(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// GetTemplate returns the named template from the cache, falling back to the embedded copy
+func GetTemplate(name string) (*Template, error) {
+	templates, err := ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i], nil
+		}
+	}
+	return nil, TemplateNotFoundError(name)
+}
+
+// ResolveTemplate returns the named template, fetching the bundles when it is not cached yet.
+// If the bundles cannot be fetched, e.g. when offline, the embedded copy is used.
+// Builds without TemplatesIndexPublicKey never fetch the bundles and quietly use the embedded copy.
+func ResolveTemplate(name string) (*Template, error) {
+	if template, err := GetTemplate(name); err == nil && template.Source == TemplateSourceCache {
+		return template, nil
+	}
+	if TemplatesIndexPublicKey == "" {
+		return GetTemplate(name)
+	}
+	if _, err := updateTemplates(AstroSQLCLITemplatesIndexURL); err != nil {
+		fmt.Println(fmt.Errorf("error updating templates %w. Using the embedded templates", err))
+	}
+	return GetTemplate(name)
+}
+
+// ExistingTemplateFiles returns the template files which are already present in dst
+func ExistingTemplateFiles(template *Template, dst string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	err := fs.WalkDir(template.Files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(path))); err == nil {
+			existing[path] = true
+		}
+		return nil
+	})
+	return existing, err
+}
+
+// CopyTemplate writes the template files into dst, overwriting any file not listed in skip
+func CopyTemplate(template *Template, dst string, skip map[string]bool) error {
+	return fs.WalkDir(template.Files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(target, templatesCacheDirMode)
+		}
+		if skip[path] {
+			return nil
+		}
+		f, err := template.Files.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return writeFile(target, f)
+	})
+}
@@ -0,0 +1,232 @@
+package sql
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTemplateBundle(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+// patchTemplatesIndexPublicKey pins a new key and returns the private key to sign the index with
+func patchTemplatesIndexPublicKey(t *testing.T) ed25519.PrivateKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	originalPublicKey := TemplatesIndexPublicKey
+	TemplatesIndexPublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	t.Cleanup(func() { TemplatesIndexPublicKey = originalPublicKey })
+	return privateKey
+}
+
+func serveTemplates(t *testing.T, bundle []byte, checksum string, signingKey ed25519.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	getIndex := func() []byte {
+		index := TemplatesIndex{Templates: []TemplateBundle{
+			{Name: "default", Version: "1.0.0", URL: server.URL + "/default-1.0.0.tar.gz", SHA256: checksum},
+		}}
+		data, err := json.Marshal(index)
+		assert.NoError(t, err)
+		return data
+	}
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(getIndex())
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc("/index.json.sig", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(signingKey, getIndex()))))
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc("/default-1.0.0.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(bundle)
+		assert.NoError(t, err)
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func patchTemplatesCacheDir(t *testing.T) {
+	originalTemplatesCacheDir := TemplatesCacheDir
+	TemplatesCacheDir = t.TempDir()
+	t.Cleanup(func() { TemplatesCacheDir = originalTemplatesCacheDir })
+}
+
+func TestUpdateTemplates(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	signingKey := patchTemplatesIndexPublicKey(t)
+	bundle := buildTemplateBundle(t, map[string]string{"workflows/example/example.sql": "SELECT 1;"})
+	sum := sha256.Sum256(bundle)
+	server := serveTemplates(t, bundle, hex.EncodeToString(sum[:]), signingKey)
+
+	bundles, err := UpdateTemplates(server.URL + "/index.json")
+	assert.NoError(t, err)
+	assert.Len(t, bundles, 1)
+
+	content, err := os.ReadFile(filepath.Join(TemplatesCacheDir, "default", "1.0.0", "workflows", "example", "example.sql"))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1;", string(content))
+
+	template, err := GetTemplate("default")
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateSourceCache, template.Source)
+	assert.Equal(t, "1.0.0", template.Version)
+}
+
+func TestUpdateTemplatesChecksumMismatch(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	signingKey := patchTemplatesIndexPublicKey(t)
+	bundle := buildTemplateBundle(t, map[string]string{"workflows/example/example.sql": "SELECT 1;"})
+	server := serveTemplates(t, bundle, "invalid", signingKey)
+
+	_, err := UpdateTemplates(server.URL + "/index.json")
+	assert.ErrorIs(t, err, errTemplateChecksumMismatch)
+	_, err = os.Stat(filepath.Join(TemplatesCacheDir, "default", "1.0.0"))
+	assert.True(t, os.IsNotExist(err))
+
+	template, err := GetTemplate("default")
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateSourceEmbedded, template.Source)
+}
+
+func TestUpdateTemplatesInvalidPath(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	signingKey := patchTemplatesIndexPublicKey(t)
+	bundle := buildTemplateBundle(t, map[string]string{"../escape.sql": "SELECT 1;"})
+	sum := sha256.Sum256(bundle)
+	server := serveTemplates(t, bundle, hex.EncodeToString(sum[:]), signingKey)
+
+	_, err := UpdateTemplates(server.URL + "/index.json")
+	assert.ErrorIs(t, err, errTemplateInvalidPath)
+}
+
+func TestUpdateTemplatesInvalidSignature(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	patchTemplatesIndexPublicKey(t)
+	bundle := buildTemplateBundle(t, map[string]string{"workflows/example/example.sql": "SELECT 1;"})
+	sum := sha256.Sum256(bundle)
+	// signed with a key other than the pinned one, e.g. a compromised index
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	server := serveTemplates(t, bundle, hex.EncodeToString(sum[:]), otherKey)
+
+	_, err = UpdateTemplates(server.URL + "/index.json")
+	assert.ErrorIs(t, err, errTemplatesIndexSignature)
+	_, err = os.Stat(filepath.Join(TemplatesCacheDir, "default", "1.0.0"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUpdateTemplatesPublicKeyNotSet(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	originalPublicKey := TemplatesIndexPublicKey
+	TemplatesIndexPublicKey = ""
+	defer func() { TemplatesIndexPublicKey = originalPublicKey }()
+
+	_, err := UpdateTemplates("http://127.0.0.1:0/index.json")
+	assert.ErrorIs(t, err, errTemplatesIndexPublicKeyNotSet)
+}
+
+func TestListTemplatesEmbedded(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	templates, err := ListTemplates()
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	assert.Equal(t, DefaultTemplateName, templates[0].Name)
+	assert.Equal(t, TemplateSourceEmbedded, templates[0].Source)
+}
+
+func TestGetTemplateNotFound(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	_, err := GetTemplate("unknown")
+	assert.EqualError(t, err, "template not found:unknown")
+}
+
+func TestResolveTemplateOffline(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	patchTemplatesIndexPublicKey(t)
+	updateTemplates = func(indexURL string) ([]TemplateBundle, error) {
+		return nil, errMock
+	}
+	defer func() { updateTemplates = UpdateTemplates }()
+
+	template, err := ResolveTemplate(DefaultTemplateName)
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateSourceEmbedded, template.Source)
+}
+
+func TestResolveTemplatePublicKeyNotSet(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	originalPublicKey := TemplatesIndexPublicKey
+	TemplatesIndexPublicKey = ""
+	updateTemplates = func(indexURL string) ([]TemplateBundle, error) {
+		t.Error("templates must not be updated without a public key")
+		return nil, errMock
+	}
+	defer func() {
+		updateTemplates = UpdateTemplates
+		TemplatesIndexPublicKey = originalPublicKey
+	}()
+
+	template, err := ResolveTemplate(DefaultTemplateName)
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateSourceEmbedded, template.Source)
+}
+
+func TestCopyTemplate(t *testing.T) {
+	patchTemplatesCacheDir(t)
+	projectDir := t.TempDir()
+	existingFile := filepath.Join(projectDir, "config", "default", "configuration.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(existingFile), 0o755))
+	assert.NoError(t, os.WriteFile(existingFile, []byte("user config"), 0o600))
+
+	template, err := GetTemplate(DefaultTemplateName)
+	assert.NoError(t, err)
+	existing, err := ExistingTemplateFiles(template, projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"config/default/configuration.yml": true}, existing)
+
+	err = CopyTemplate(template, projectDir, existing)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(existingFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "user config", string(content))
+	_, err = os.Stat(filepath.Join(projectDir, "workflows", "example_basic_transform", "top_animations.sql"))
+	assert.NoError(t, err)
+}
+
+func TestExtractTarGzMaxSize(t *testing.T) {
+	bundle := buildTemplateBundle(t, map[string]string{"a.sql": "SELECT 1;", "b.sql": "SELECT 2;"})
+
+	err := ExtractTarGz(bytes.NewReader(bundle), t.TempDir(), 18)
+	assert.NoError(t, err)
+
+	dst := t.TempDir()
+	err = ExtractTarGz(bytes.NewReader(bundle), dst, 17)
+	assert.ErrorIs(t, err, errTarGzTooLarge)
+}